@@ -70,6 +70,49 @@ type Preset struct {
 	// Premium color palette
 	GlowR, GlowG, GlowB uint8
 	GlowIntensity       float32
+	// Blend is how particles combine with the pixels below them
+	Blend BlendMode
+}
+
+// BlendMode selects how particles combine with the pixels below them,
+// matching components.BlendMode of the native version
+type BlendMode int
+
+const (
+	BlendAlpha BlendMode = iota
+	BlendAdditive
+	BlendMultiply
+	BlendScreen
+)
+
+// ebitenBlend returns the Ebitengine blend for the mode. Ebitengine works
+// with premultiplied colors, so screen is (1, 1 - src) like the native
+// version.
+func (m BlendMode) ebitenBlend() ebiten.Blend {
+	switch m {
+	case BlendAdditive:
+		return ebiten.BlendLighter
+	case BlendMultiply:
+		return ebiten.Blend{
+			BlendFactorSourceRGB:        ebiten.BlendFactorDestinationColor,
+			BlendFactorSourceAlpha:      ebiten.BlendFactorZero,
+			BlendFactorDestinationRGB:   ebiten.BlendFactorOneMinusSourceAlpha,
+			BlendFactorDestinationAlpha: ebiten.BlendFactorOne,
+			BlendOperationRGB:           ebiten.BlendOperationAdd,
+			BlendOperationAlpha:         ebiten.BlendOperationAdd,
+		}
+	case BlendScreen:
+		return ebiten.Blend{
+			BlendFactorSourceRGB:        ebiten.BlendFactorOne,
+			BlendFactorSourceAlpha:      ebiten.BlendFactorOne,
+			BlendFactorDestinationRGB:   ebiten.BlendFactorOneMinusSourceColor,
+			BlendFactorDestinationAlpha: ebiten.BlendFactorOneMinusSourceAlpha,
+			BlendOperationRGB:           ebiten.BlendOperationAdd,
+			BlendOperationAlpha:         ebiten.BlendOperationAdd,
+		}
+	default:
+		return ebiten.BlendSourceOver
+	}
 }
 
 var presets = []Preset{
@@ -79,6 +122,7 @@ var presets = []Preset{
 		MinSize: 1.5, MaxSize: 4.0, MinTTL: 4.0, MaxTTL: 7.0,
 		MinVel: -30, MaxVel: 30, SpawnPattern: "center", SpawnRate: 150,
 		GlowR: 150, GlowG: 100, GlowB: 255, GlowIntensity: 0.8,
+		Blend: BlendAdditive,
 	},
 	{
 		Name: "Firework", StartR: 255, StartG: 200, StartB: 50,
@@ -86,6 +130,7 @@ var presets = []Preset{
 		MinSize: 2.0, MaxSize: 5.0, MinTTL: 1.5, MaxTTL: 3.0,
 		MinVel: -150, MaxVel: 150, SpawnPattern: "center", SpawnRate: 200,
 		GlowR: 255, GlowG: 180, GlowB: 50, GlowIntensity: 1.0,
+		Blend: BlendAdditive,
 	},
 	{
 		Name: "Swarm", StartR: 50, StartG: 255, StartB: 100,
@@ -107,6 +152,7 @@ var presets = []Preset{
 		MinSize: 1.0, MaxSize: 6.0, MinTTL: 2.0, MaxTTL: 5.0,
 		MinVel: -100, MaxVel: 100, SpawnPattern: "edges", SpawnRate: 250,
 		GlowR: 255, GlowG: 100, GlowB: 100, GlowIntensity: 0.9,
+		Blend: BlendScreen,
 	},
 }
 
@@ -842,6 +888,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	screen.Fill(color.RGBA{10, 10, 20, 255})
 
 	preset := g.preset
+	blend := preset.Blend.ebitenBlend()
 
 	for i := range g.particles {
 		p := &g.particles[i]
@@ -857,13 +904,13 @@ func (g *Game) Draw(screen *ebiten.Image) {
 			// Draw glow passes
 			for pass := 0; pass < g.qualitySettings.GlowPasses; pass++ {
 				glowSize := p.Radius * (2.0 + float32(pass)*1.2)
-				drawCircleFast(screen, p.X, p.Y, glowSize, glowCol)
+				drawDisc(screen, p.X, p.Y, glowSize, glowCol, blend)
 			}
 		}
 
 		// Main particle
 		col := color.RGBA{p.R, p.G, p.B, p.A}
-		drawCircleFast(screen, p.X, p.Y, p.Radius, col)
+		drawDisc(screen, p.X, p.Y, p.Radius, col, blend)
 	}
 
 	// Debug overlay
@@ -1078,28 +1125,45 @@ func (g *Game) drawTouchButton(screen *ebiten.Image, x, y, w, h int, label strin
 	ebitenutil.DebugPrintAt(screen, label, labelX, labelY)
 }
 
-// Fast circle drawing using Ebitengine's optimized methods
-func drawCircleFast(screen *ebiten.Image, cx, cy, radius float32, col color.RGBA) {
-	r := int(radius)
-	if r < 1 {
-		r = 1
-	}
-	// Use squared distance check for filled circle
-	r2 := r * r
-	for y := -r; y <= r; y++ {
-		for x := -r; x <= r; x++ {
-			if x*x+y*y <= r2 {
-				px, py := int(cx)+x, int(cy)+y
-				if px >= 0 && px < screenWidth && py >= 0 && py < screenHeight {
-					screen.Set(px, py, col)
-				}
-			}
+// discRadius is the radius of discImage in pixels
+const discRadius = 16
+
+// discImage is a white anti-aliased disc that particles are drawn with,
+// so Ebitengine can batch them and apply blend modes
+var discImage = newDisc(discRadius)
+
+func newDisc(radius int) *ebiten.Image {
+	size := radius * 2
+	pixels := make([]byte, size*size*4)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx := float64(x) + 0.5 - float64(radius)
+			dy := float64(y) + 0.5 - float64(radius)
+			// Coverage falls off over the outermost pixel
+			cover := math.Max(0, math.Min(1, float64(radius)-math.Hypot(dx, dy)))
+			v := byte(cover * 255)
+			i := (y*size + x) * 4
+			pixels[i], pixels[i+1], pixels[i+2], pixels[i+3] = v, v, v, v
 		}
 	}
+	img := ebiten.NewImage(size, size)
+	img.WritePixels(pixels)
+	return img
 }
 
-func drawCircle(screen *ebiten.Image, cx, cy, radius float32, col color.RGBA) {
-	drawCircleFast(screen, cx, cy, radius, col)
+// drawDisc draws a filled circle with the given blend. col is a straight
+// (not premultiplied) color, like all particle colors
+func drawDisc(screen *ebiten.Image, cx, cy, radius float32, col color.RGBA, blend ebiten.Blend) {
+	scale := float64(max(radius, 0.5)) / discRadius
+	var op ebiten.DrawImageOptions
+	op.GeoM.Translate(-discRadius, -discRadius)
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(float64(cx), float64(cy))
+	a := float32(col.A) / 255
+	op.ColorScale.Scale(float32(col.R)/255*a, float32(col.G)/255*a, float32(col.B)/255*a, a)
+	op.Blend = blend
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(discImage, &op)
 }
 
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
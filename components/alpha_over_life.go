@@ -0,0 +1,23 @@
+package components
+
+import "github.com/deltatree/showcase/curves"
+
+// AlphaOverLife drives an entity's opacity by a curve over its lifetime.
+// ColorSystem sets Color.A = Color.StartA * Curve.At(progress) after the
+// color interpolation, so the RGB channels keep following Color while the
+// alpha follows the curve. With a Gradient, the curve scales the
+// gradient's alpha instead of StartA.
+//
+// Example of a fade-out holding full opacity early in the lifetime:
+//
+//	aol := components.NewAlphaOverLife(curves.FadeOutQuad)
+type AlphaOverLife struct {
+	// Curve maps lifetime progress to an alpha multiplier in [0, 1].
+	Curve curves.Curve
+}
+
+// Mask returns the component mask for AlphaOverLife.
+func (a *AlphaOverLife) Mask() uint64 { return MaskAlphaOverLife }
+
+// NewAlphaOverLife creates an AlphaOverLife driven by curve.
+func NewAlphaOverLife(curve curves.Curve) *AlphaOverLife { return &AlphaOverLife{Curve: curve} }
@@ -0,0 +1,21 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/deltatree/showcase/curves"
+)
+
+func TestAlphaOverLife_Mask(t *testing.T) {
+	c := NewAlphaOverLife(curves.Linear)
+	if c.Mask() != MaskAlphaOverLife {
+		t.Errorf("AlphaOverLife.Mask() = %v, want %v", c.Mask(), MaskAlphaOverLife)
+	}
+}
+
+func TestAlphaOverLife_Curve(t *testing.T) {
+	c := NewAlphaOverLife(curves.FadeOutQuad)
+	if got := c.Curve.At(0.5); got != 0.75 {
+		t.Errorf("AlphaOverLife.Curve.At(0.5) = %v, want 0.75", got)
+	}
+}
@@ -0,0 +1,54 @@
+package components
+
+// BlendMode selects how a particle's color is combined with the pixels
+// already drawn. The zero value is regular alpha blending.
+type BlendMode uint8
+
+// Available blend modes.
+const (
+	// BlendAlpha paints particles over each other by their alpha.
+	BlendAlpha BlendMode = iota
+	// BlendAdditive adds particle colors, so dense regions brighten
+	// towards white. Suited for fire, sparks and stars.
+	BlendAdditive
+	// BlendMultiply multiplies particle colors with the background,
+	// darkening it like ink or smoke.
+	BlendMultiply
+	// BlendScreen brightens like additive blending but saturates softly
+	// instead of clipping.
+	BlendScreen
+)
+
+// String returns the name of the blend mode.
+func (m BlendMode) String() string {
+	switch m {
+	case BlendAdditive:
+		return "Additive"
+	case BlendMultiply:
+		return "Multiply"
+	case BlendScreen:
+		return "Screen"
+	default:
+		return "Alpha"
+	}
+}
+
+// Blend overrides the render system's blend mode for one entity, e.g. to
+// draw additive sparks on top of alpha-blended smoke.
+//
+// A Blend holds no per-entity state, so one instance can be shared by
+// many particles.
+//
+// Example:
+//
+//	sparks := components.NewBlend(components.BlendAdditive)
+type Blend struct {
+	// Mode is the blend mode the entity is drawn with.
+	Mode BlendMode
+}
+
+// Mask returns the component mask for Blend.
+func (b *Blend) Mask() uint64 { return MaskBlend }
+
+// NewBlend creates a Blend with the given mode.
+func NewBlend(mode BlendMode) *Blend { return &Blend{Mode: mode} }
@@ -0,0 +1,28 @@
+package components
+
+import "testing"
+
+func TestBlend_Mask(t *testing.T) {
+	b := NewBlend(BlendAdditive)
+	if b.Mask() != MaskBlend {
+		t.Errorf("Blend.Mask() = %v, want %v", b.Mask(), MaskBlend)
+	}
+}
+
+func TestBlendMode_String(t *testing.T) {
+	tests := []struct {
+		mode BlendMode
+		want string
+	}{
+		{BlendAlpha, "Alpha"},
+		{BlendAdditive, "Additive"},
+		{BlendMultiply, "Multiply"},
+		{BlendScreen, "Screen"},
+		{BlendMode(99), "Alpha"},
+	}
+	for _, tt := range tests {
+		if got := tt.mode.String(); got != tt.want {
+			t.Errorf("BlendMode(%d).String() = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package components
+
+import "math"
+
+// Zoom limits of a Camera.
+const (
+	MinZoom = 0.25
+	MaxZoom = 8.0
+)
+
+// Camera views the world from a position, zoom and rotation. The camera's
+// X, Y is the world point shown at the center of the screen; a zoom of 1
+// with no rotation maps world pixels 1:1 to screen pixels.
+//
+// RenderSystem draws the world through the first Camera entity it finds
+// and sets its Pulse, InputSystem zooms it with the mouse wheel and pans
+// it by dragging, and CameraSystem moves it towards the entity named by
+// Follow.
+//
+// Example of a camera following the mouse attractor at double zoom:
+//
+//	cam := components.NewCamera().With(640, 360).WithZoom(2).WithFollow("mouse-attractor")
+type Camera struct {
+	// X is the horizontal world position at the screen center.
+	X float32
+	// Y is the vertical world position at the screen center.
+	Y float32
+	// Zoom scales the world; values above 1 magnify.
+	Zoom float32
+	// Pulse is the momentary extra zoom of the screen pulse, 1 when not
+	// pulsing. Conversions between screen and world include it, so the
+	// world under the cursor is the one drawn there.
+	Pulse float32
+	// Rotation turns the view clockwise, in degrees.
+	Rotation float32
+	// Follow is the id of the entity the camera follows, if not empty.
+	Follow string
+	// Smoothing is the rate in 1/s at which the camera catches up with
+	// the followed entity; 0 snaps to it.
+	Smoothing float32
+}
+
+// Mask returns the component mask for Camera.
+func (c *Camera) Mask() uint64 { return MaskCamera }
+
+// NewCamera creates a Camera at the origin with zoom 1.
+func NewCamera() *Camera { return &Camera{Zoom: 1, Pulse: 1, Smoothing: 4} }
+
+// With sets the viewed world position and returns the camera for chaining.
+func (c *Camera) With(x, y float32) *Camera { c.X = x; c.Y = y; return c }
+
+// WithZoom sets the zoom, clamped to [MinZoom, MaxZoom], and returns the
+// camera for chaining.
+func (c *Camera) WithZoom(zoom float32) *Camera { c.Zoom = clampZoom(zoom); return c }
+
+// WithRotation sets the rotation in degrees and returns the camera for
+// chaining.
+func (c *Camera) WithRotation(degrees float32) *Camera { c.Rotation = degrees; return c }
+
+// WithFollow sets the id of the entity to follow and returns the camera
+// for chaining.
+func (c *Camera) WithFollow(id string) *Camera { c.Follow = id; return c }
+
+// WithSmoothing sets the follow rate and returns the camera for chaining.
+func (c *Camera) WithSmoothing(rate float32) *Camera { c.Smoothing = rate; return c }
+
+// ViewZoom returns the zoom the world is drawn with: Zoom times Pulse.
+func (c *Camera) ViewZoom() float32 {
+	if c.Pulse <= 0 {
+		return c.Zoom
+	}
+	return c.Zoom * c.Pulse
+}
+
+func clampZoom(zoom float32) float32 {
+	return min(max(zoom, MinZoom), MaxZoom)
+}
+
+// rotation returns the sine and cosine of the camera rotation.
+func (c *Camera) rotation() (sin, cos float32) {
+	if c.Rotation == 0 {
+		return 0, 1
+	}
+	s, co := math.Sincos(float64(c.Rotation) * math.Pi / 180)
+	return float32(s), float32(co)
+}
+
+// ScreenToWorld converts a screen position on a width × height screen to
+// world coordinates.
+func (c *Camera) ScreenToWorld(sx, sy, width, height float32) (x, y float32) {
+	zoom := c.ViewZoom()
+	dx, dy := (sx-width/2)/zoom, (sy-height/2)/zoom
+	sin, cos := c.rotation()
+	return c.X + dx*cos + dy*sin, c.Y - dx*sin + dy*cos
+}
+
+// WorldToScreen converts a world position to screen coordinates on a
+// width × height screen.
+func (c *Camera) WorldToScreen(x, y, width, height float32) (sx, sy float32) {
+	dx, dy := x-c.X, y-c.Y
+	sin, cos := c.rotation()
+	zoom := c.ViewZoom()
+	return width/2 + (dx*cos-dy*sin)*zoom, height/2 + (dx*sin+dy*cos)*zoom
+}
+
+// Pan moves the view by a screen-space distance, e.g. a mouse drag: the
+// world follows the cursor.
+func (c *Camera) Pan(dx, dy float32) {
+	sin, cos := c.rotation()
+	zoom := c.ViewZoom()
+	c.X -= (dx*cos + dy*sin) / zoom
+	c.Y -= (-dx*sin + dy*cos) / zoom
+}
+
+// ZoomAt multiplies the zoom by factor, keeping the world point under the
+// screen position (sx, sy) in place.
+func (c *Camera) ZoomAt(factor, sx, sy, width, height float32) {
+	wx, wy := c.ScreenToWorld(sx, sy, width, height)
+	c.Zoom = clampZoom(c.Zoom * factor)
+	nx, ny := c.ScreenToWorld(sx, sy, width, height)
+	c.X += wx - nx
+	c.Y += wy - ny
+}
@@ -0,0 +1,99 @@
+package components
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-3
+}
+
+func TestCamera_Mask(t *testing.T) {
+	c := NewCamera()
+	if c.Mask() != MaskCamera {
+		t.Errorf("Camera.Mask() = %v, want %v", c.Mask(), MaskCamera)
+	}
+}
+
+func TestCamera_WithZoom(t *testing.T) {
+	if z := NewCamera().WithZoom(100).Zoom; z != MaxZoom {
+		t.Errorf("WithZoom(100) = %v, want %v", z, MaxZoom)
+	}
+	if z := NewCamera().WithZoom(0).Zoom; z != MinZoom {
+		t.Errorf("WithZoom(0) = %v, want %v", z, MinZoom)
+	}
+}
+
+func TestCamera_ScreenToWorld(t *testing.T) {
+	c := NewCamera().With(640, 360)
+	if x, y := c.ScreenToWorld(100, 50, 1280, 720); x != 100 || y != 50 {
+		t.Errorf("identity camera: ScreenToWorld = (%v, %v), want (100, 50)", x, y)
+	}
+
+	c.WithZoom(2)
+	if x, y := c.ScreenToWorld(740, 360, 1280, 720); x != 690 || y != 360 {
+		t.Errorf("zoom 2: ScreenToWorld = (%v, %v), want (690, 360)", x, y)
+	}
+
+	// A quarter turn clockwise turns the world's downward axis to the left
+	c.WithZoom(1).WithRotation(90)
+	if x, y := c.WorldToScreen(640, 460, 1280, 720); !near(x, 540) || !near(y, 360) {
+		t.Errorf("rotated: WorldToScreen = (%v, %v), want (540, 360)", x, y)
+	}
+}
+
+func TestCamera_RoundTrip(t *testing.T) {
+	c := NewCamera().With(300, -20).WithZoom(1.7).WithRotation(33)
+	x, y := c.ScreenToWorld(123, 456, 800, 600)
+	if sx, sy := c.WorldToScreen(x, y, 800, 600); !near(sx, 123) || !near(sy, 456) {
+		t.Errorf("round trip = (%v, %v), want (123, 456)", sx, sy)
+	}
+}
+
+func TestCamera_Pan(t *testing.T) {
+	c := NewCamera().With(640, 360).WithZoom(2).WithRotation(45)
+	wx, wy := c.ScreenToWorld(200, 200, 1280, 720)
+	c.Pan(30, -10)
+	// The world point under the cursor moves with the drag
+	if x, y := c.ScreenToWorld(230, 190, 1280, 720); !near(x, wx) || !near(y, wy) {
+		t.Errorf("after Pan: (%v, %v), want (%v, %v)", x, y, wx, wy)
+	}
+}
+
+func TestCamera_Pulse(t *testing.T) {
+	c := NewCamera().With(640, 360).WithZoom(2)
+	c.Pulse = 1.5
+	if z := c.ViewZoom(); z != 3 {
+		t.Errorf("ViewZoom() = %v, want 3", z)
+	}
+	// 300 screen pixels right of the center are 100 world pixels at zoom 3
+	if x, _ := c.ScreenToWorld(940, 360, 1280, 720); !near(x, 740) {
+		t.Errorf("ScreenToWorld() x = %v, want 740", x)
+	}
+	if sx, _ := c.WorldToScreen(740, 360, 1280, 720); !near(sx, 940) {
+		t.Errorf("WorldToScreen() x = %v, want 940", sx)
+	}
+
+	c.Pulse = 0
+	if z := c.ViewZoom(); z != 2 {
+		t.Errorf("ViewZoom() without pulse = %v, want 2", z)
+	}
+}
+
+func TestCamera_ZoomAt(t *testing.T) {
+	c := NewCamera().With(640, 360)
+	wx, wy := c.ScreenToWorld(100, 600, 1280, 720)
+	c.ZoomAt(1.5, 100, 600, 1280, 720)
+	if c.Zoom != 1.5 {
+		t.Errorf("Zoom = %v, want 1.5", c.Zoom)
+	}
+	if x, y := c.ScreenToWorld(100, 600, 1280, 720); !near(x, wx) || !near(y, wy) {
+		t.Errorf("point under cursor moved to (%v, %v), want (%v, %v)", x, y, wx, wy)
+	}
+
+	c.ZoomAt(100, 0, 0, 1280, 720)
+	if c.Zoom != MaxZoom {
+		t.Errorf("Zoom = %v, want clamped to %v", c.Zoom, MaxZoom)
+	}
+}
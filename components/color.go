@@ -5,7 +5,8 @@ package components
 // over the entity's lifetime.
 //
 // The color system interpolates between StartRGBA and EndRGBA based on
-// the entity's Lifetime progress, creating smooth fade effects.
+// the entity's Lifetime progress, creating smooth fade effects. Space selects
+// the interpolation space; the default interpolates raw sRGB bytes.
 type Color struct {
 	// R, G, B, A represent the current RGBA color values (0-255).
 	R, G, B, A uint8
@@ -13,6 +14,8 @@ type Color struct {
 	StartR, StartG, StartB, StartA uint8
 	// EndR, EndG, EndB, EndA are the final colors at lifetime end.
 	EndR, EndG, EndB, EndA uint8
+	// Space is the color space the gradient is interpolated in.
+	Space ColorSpace
 }
 
 // Mask returns the component mask for Color.
@@ -41,3 +44,9 @@ func (c *Color) WithGradient(sr, sg, sb, sa, er, eg, eb, ea uint8) *Color {
 	c.R, c.G, c.B, c.A = sr, sg, sb, sa
 	return c
 }
+
+// WithSpace sets the interpolation color space and returns the color for chaining.
+func (c *Color) WithSpace(space ColorSpace) *Color {
+	c.Space = space
+	return c
+}
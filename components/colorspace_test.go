@@ -0,0 +1,69 @@
+package components
+
+import (
+	"testing"
+)
+
+func TestColorSpace_Endpoints(t *testing.T) {
+	for _, cs := range []ColorSpace{SpaceSRGB, SpaceLinearRGB, SpaceHSV, SpaceOKLab} {
+		r, g, b, a := cs.Mix(255, 0, 255, 255, 0, 255, 255, 0, 0)
+		if r != 255 || g != 0 || b != 255 || a != 255 {
+			t.Errorf("%v Mix(t=0) = (%v, %v, %v, %v), want (255, 0, 255, 255)", cs, r, g, b, a)
+		}
+		r, g, b, a = cs.Mix(255, 0, 255, 255, 0, 255, 255, 0, 1)
+		if r != 0 || g != 255 || b != 255 || a != 0 {
+			t.Errorf("%v Mix(t=1) = (%v, %v, %v, %v), want (0, 255, 255, 0)", cs, r, g, b, a)
+		}
+	}
+}
+
+func TestColorSpace_HSVMidpointSaturated(t *testing.T) {
+	// Saturation of the magenta → cyan midpoint (max - min channel)
+	spread := func(cs ColorSpace) int {
+		r, g, b, _ := cs.Mix(255, 0, 255, 255, 0, 255, 255, 255, 0.5)
+		return int(max(r, g, b)) - int(min(r, g, b))
+	}
+	if hsv, srgb := spread(SpaceHSV), spread(SpaceSRGB); hsv <= srgb {
+		t.Errorf("HSV midpoint spread = %d, want more saturated than sRGB (%d)", hsv, srgb)
+	}
+}
+
+func TestColorSpace_OKLabMidpointBrighter(t *testing.T) {
+	// Red → green passes through a dark olive in sRGB.
+	sum := func(cs ColorSpace) int {
+		r, g, b, _ := cs.Mix(255, 0, 0, 255, 0, 255, 0, 255, 0.5)
+		return int(r) + int(g) + int(b)
+	}
+	if ok, srgb := sum(SpaceOKLab), sum(SpaceSRGB); ok <= srgb {
+		t.Errorf("OKLab midpoint brightness = %d, want brighter than sRGB (%d)", ok, srgb)
+	}
+}
+
+func TestColorSpace_HSVShortestHue(t *testing.T) {
+	// Red (0°) → magenta (300°) goes backwards through 330°, not via green.
+	r, g, b, _ := SpaceHSV.Mix(255, 0, 0, 255, 255, 0, 255, 255, 0.5)
+	if g != 0 || r != 255 || b == 0 {
+		t.Errorf("HSV midpoint = (%v, %v, %v), want a pink-red without green", r, g, b)
+	}
+}
+
+func TestColorSpace_LinearBrighter(t *testing.T) {
+	s, _, _, _ := SpaceSRGB.Mix(0, 0, 0, 255, 255, 255, 255, 255, 0.5)
+	l, _, _, _ := SpaceLinearRGB.Mix(0, 0, 0, 255, 255, 255, 255, 255, 0.5)
+	if l <= s {
+		t.Errorf("linear midpoint = %v, want brighter than sRGB midpoint %v", l, s)
+	}
+}
+
+func TestColorSpace_String(t *testing.T) {
+	if SpaceOKLab.String() != "OKLab" || SpaceSRGB.String() != "sRGB" {
+		t.Errorf("unexpected names %q, %q", SpaceOKLab.String(), SpaceSRGB.String())
+	}
+}
+
+func TestColor_WithSpace(t *testing.T) {
+	c := NewColor().WithSpace(SpaceHSV)
+	if c.Space != SpaceHSV {
+		t.Errorf("WithSpace() = %v, want HSV", c.Space)
+	}
+}
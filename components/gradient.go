@@ -0,0 +1,149 @@
+package components
+
+import (
+	"sort"
+
+	"github.com/deltatree/showcase/curves"
+)
+
+// Easing shapes the interpolation between two gradient stops.
+// The zero value is linear interpolation.
+type Easing uint8
+
+// Available easing functions.
+const (
+	EaseLinear Easing = iota
+	EaseInQuad
+	EaseOutQuad
+	EaseInOutQuad
+	EaseInCubic
+	EaseOutCubic
+	EaseInOutCubic
+	EaseSmoothStep
+	// EaseStep holds the segment's start color until the next stop.
+	EaseStep
+)
+
+// easingCurves maps each Easing to its curve in the curves package.
+var easingCurves = [...]curves.Curve{
+	EaseLinear:     curves.Linear,
+	EaseInQuad:     curves.InQuad,
+	EaseOutQuad:    curves.OutQuad,
+	EaseInOutQuad:  curves.InOutQuad,
+	EaseInCubic:    curves.InCubic,
+	EaseOutCubic:   curves.OutCubic,
+	EaseInOutCubic: curves.InOutCubic,
+	EaseSmoothStep: curves.SmoothStep,
+	EaseStep:       curves.Constant(0),
+}
+
+// Curve returns the curves.Curve implementing the easing. Unknown values
+// fall back to linear.
+func (e Easing) Curve() curves.Curve {
+	if int(e) < len(easingCurves) {
+		return easingCurves[e]
+	}
+	return curves.Linear
+}
+
+// Apply maps t in [0, 1] through the easing function.
+func (e Easing) Apply(t float32) float32 {
+	return e.Curve().At(t)
+}
+
+// GradientStop is one color of a Gradient.
+type GradientStop struct {
+	// Pos is the lifetime progress (0.0 to 1.0) at which the color is reached.
+	Pos float32
+	// R, G, B, A is the color at Pos.
+	R, G, B, A uint8
+	// Ease shapes the transition from this stop to the next one.
+	Ease Easing
+}
+
+// Gradient colors an entity along N stops over its lifetime. It replaces
+// the linear start-to-end interpolation of Color: ColorSystem evaluates the
+// gradient at Lifetime.Progress() and writes the result into Color.
+//
+// Stops are kept sorted by position. Before the first stop the first color
+// is used, after the last stop the last color.
+//
+// Segments are interpolated in Space (sRGB bytes by default).
+//
+// A gradient holds no per-entity state, so one instance can be shared by
+// many particles.
+//
+// Example of a fire ember cooling down into smoke:
+//
+//	fire := components.NewGradient().
+//	    WithStop(0.0, 255, 255, 255, 255).
+//	    WithStop(0.1, 255, 230, 80, 255).
+//	    WithStop(0.3, 255, 140, 20, 240).
+//	    WithStop(0.5, 200, 40, 10, 200).
+//	    WithEasedStop(0.7, 90, 90, 90, 120, components.EaseOutQuad).
+//	    WithStop(1.0, 60, 60, 60, 0)
+type Gradient struct {
+	// Stops are the gradient colors, sorted by Pos.
+	Stops []GradientStop
+	// Space is the color space the segments are interpolated in.
+	Space ColorSpace
+}
+
+// Mask returns the component mask for Gradient.
+func (g *Gradient) Mask() uint64 { return MaskGradient }
+
+// NewGradient creates an empty Gradient. Add colors with WithStop.
+func NewGradient() *Gradient { return &Gradient{} }
+
+// WithStop adds a stop with linear easing towards the next stop and returns
+// the gradient for chaining.
+func (g *Gradient) WithStop(pos float32, r, gr, b, a uint8) *Gradient {
+	return g.WithEasedStop(pos, r, gr, b, a, EaseLinear)
+}
+
+// WithEasedStop adds a stop whose transition to the next stop is shaped by
+// ease and returns the gradient for chaining.
+func (g *Gradient) WithEasedStop(pos float32, r, gr, b, a uint8, ease Easing) *Gradient {
+	stop := GradientStop{Pos: pos, R: r, G: gr, B: b, A: a, Ease: ease}
+	i := sort.Search(len(g.Stops), func(i int) bool { return g.Stops[i].Pos > pos })
+	g.Stops = append(g.Stops, GradientStop{})
+	copy(g.Stops[i+1:], g.Stops[i:])
+	g.Stops[i] = stop
+	return g
+}
+
+// WithSpace sets the interpolation color space and returns the gradient for
+// chaining.
+func (g *Gradient) WithSpace(space ColorSpace) *Gradient {
+	g.Space = space
+	return g
+}
+
+// Evaluate returns the gradient color at progress t. An empty gradient
+// returns opaque white.
+func (g *Gradient) Evaluate(t float32) (r, gr, b, a uint8) {
+	n := len(g.Stops)
+	if n == 0 {
+		return 255, 255, 255, 255
+	}
+	if t <= g.Stops[0].Pos {
+		s := g.Stops[0]
+		return s.R, s.G, s.B, s.A
+	}
+	if t >= g.Stops[n-1].Pos {
+		s := g.Stops[n-1]
+		return s.R, s.G, s.B, s.A
+	}
+
+	i := sort.Search(n, func(i int) bool { return g.Stops[i].Pos > t }) - 1
+	from, to := g.Stops[i], g.Stops[i+1]
+	local := (t - from.Pos) / (to.Pos - from.Pos)
+	f := from.Ease.Apply(local)
+
+	return g.Space.Mix(from.R, from.G, from.B, from.A, to.R, to.G, to.B, to.A, f)
+}
+
+// mix interpolates between two channel values.
+func mix(a, b uint8, t float32) uint8 {
+	return uint8(float32(a) + (float32(b)-float32(a))*t + 0.5)
+}
@@ -0,0 +1,82 @@
+package components
+
+import (
+	"testing"
+)
+
+func TestGradient_Mask(t *testing.T) {
+	g := NewGradient()
+	if g.Mask() != MaskGradient {
+		t.Errorf("Gradient.Mask() = %v, want %v", g.Mask(), MaskGradient)
+	}
+}
+
+func TestGradient_StopsSorted(t *testing.T) {
+	g := NewGradient().
+		WithStop(1.0, 0, 0, 0, 0).
+		WithStop(0.0, 255, 255, 255, 255).
+		WithStop(0.5, 255, 0, 0, 255)
+	for i, want := range []float32{0, 0.5, 1} {
+		if g.Stops[i].Pos != want {
+			t.Errorf("Stops[%d].Pos = %v, want %v", i, g.Stops[i].Pos, want)
+		}
+	}
+}
+
+func TestGradient_Evaluate(t *testing.T) {
+	g := NewGradient().
+		WithStop(0.0, 255, 255, 255, 255).
+		WithStop(0.5, 255, 0, 0, 255).
+		WithStop(1.0, 0, 0, 0, 0)
+
+	tests := []struct {
+		t          float32
+		r, g, b, a uint8
+	}{
+		{-1, 255, 255, 255, 255},
+		{0, 255, 255, 255, 255},
+		{0.25, 255, 128, 128, 255},
+		{0.5, 255, 0, 0, 255},
+		{0.75, 128, 0, 0, 128},
+		{2, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		r, gr, b, a := g.Evaluate(tt.t)
+		if r != tt.r || gr != tt.g || b != tt.b || a != tt.a {
+			t.Errorf("Evaluate(%v) = (%v, %v, %v, %v), want (%v, %v, %v, %v)",
+				tt.t, r, gr, b, a, tt.r, tt.g, tt.b, tt.a)
+		}
+	}
+}
+
+func TestGradient_EvaluateEased(t *testing.T) {
+	g := NewGradient().
+		WithEasedStop(0, 0, 0, 0, 0, EaseStep).
+		WithEasedStop(0.5, 100, 100, 100, 100, EaseInQuad).
+		WithStop(1, 200, 200, 200, 200)
+
+	if r, _, _, _ := g.Evaluate(0.49); r != 0 {
+		t.Errorf("step segment should hold start color, got %v", r)
+	}
+	if r, _, _, _ := g.Evaluate(0.75); r != 125 {
+		t.Errorf("ease-in quad at half segment = %v, want 125", r)
+	}
+}
+
+func TestGradient_EvaluateEmpty(t *testing.T) {
+	r, g, b, a := NewGradient().Evaluate(0.5)
+	if r != 255 || g != 255 || b != 255 || a != 255 {
+		t.Errorf("empty gradient = (%v, %v, %v, %v), want opaque white", r, g, b, a)
+	}
+}
+
+func TestEasing_Endpoints(t *testing.T) {
+	for e := EaseLinear; e < EaseStep; e++ {
+		if got := e.Apply(0); got != 0 {
+			t.Errorf("Easing(%d).Apply(0) = %v, want 0", e, got)
+		}
+		if got := e.Apply(1); got != 1 {
+			t.Errorf("Easing(%d).Apply(1) = %v, want 1", e, got)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package components
+
+// RenderLayer is a group of entities drawn together. Layers are drawn in
+// ascending order, so later layers cover earlier ones.
+type RenderLayer uint8
+
+// Render layers, back to front.
+const (
+	// LayerBackground is drawn before any particle, e.g. for scenery.
+	LayerBackground RenderLayer = iota
+	// LayerParticles holds all entities without a Layer component.
+	LayerParticles
+	// LayerForeground is drawn over the particles, e.g. for obstacles
+	// and attractor gizmos.
+	LayerForeground
+	// LayerUI is drawn last, without screen shake or post-processing.
+	LayerUI
+
+	// LayerCount is the number of render layers.
+	LayerCount = iota
+)
+
+// String returns the name of the layer.
+func (l RenderLayer) String() string {
+	switch l {
+	case LayerBackground:
+		return "Background"
+	case LayerParticles:
+		return "Particles"
+	case LayerForeground:
+		return "Foreground"
+	case LayerUI:
+		return "UI"
+	default:
+		return "Unknown"
+	}
+}
+
+// Layer places an entity in a render layer. Within a layer, entities are
+// drawn by ascending Z; entities with equal Z keep the order they are
+// stored in. Entities without a Layer are drawn in LayerParticles at Z 0.
+//
+// Example of an obstacle drawn above the particles:
+//
+//	layer := components.NewLayer(components.LayerForeground).WithZ(10)
+type Layer struct {
+	// Layer is the render layer the entity is drawn in.
+	Layer RenderLayer
+	// Z orders entities within the layer.
+	Z float32
+}
+
+// Mask returns the component mask for Layer.
+func (l *Layer) Mask() uint64 { return MaskLayer }
+
+// NewLayer creates a Layer in the given render layer at Z 0.
+func NewLayer(layer RenderLayer) *Layer { return &Layer{Layer: layer} }
+
+// WithZ sets the order within the layer and returns the Layer for chaining.
+func (l *Layer) WithZ(z float32) *Layer {
+	l.Z = z
+	return l
+}
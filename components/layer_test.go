@@ -0,0 +1,35 @@
+package components
+
+import "testing"
+
+func TestLayer_Mask(t *testing.T) {
+	l := NewLayer(LayerForeground)
+	if l.Mask() != MaskLayer {
+		t.Errorf("Layer.Mask() = %v, want %v", l.Mask(), MaskLayer)
+	}
+}
+
+func TestLayer_WithZ(t *testing.T) {
+	l := NewLayer(LayerBackground).WithZ(-2.5)
+	if l.Layer != LayerBackground || l.Z != -2.5 {
+		t.Errorf("Layer = %+v, want Background at Z -2.5", l)
+	}
+}
+
+func TestRenderLayer_String(t *testing.T) {
+	tests := []struct {
+		layer RenderLayer
+		want  string
+	}{
+		{LayerBackground, "Background"},
+		{LayerParticles, "Particles"},
+		{LayerForeground, "Foreground"},
+		{LayerUI, "UI"},
+		{RenderLayer(LayerCount), "Unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.layer.String(); got != tt.want {
+			t.Errorf("RenderLayer(%d).String() = %q, want %q", tt.layer, got, tt.want)
+		}
+	}
+}
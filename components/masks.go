@@ -8,8 +8,16 @@
 //
 // Position, Velocity, and Acceleration form the physics foundation.
 // Color and Size handle visual representation with gradient interpolation.
+// Gradient colors particles along multiple eased color stops.
+// SizeOverLife, AlphaOverLife and SpeedOverLife animate particles along curves.
+// Trail records recent positions for drawing motion trails.
+// Blend selects a per-entity blend mode (alpha, additive, multiply, screen).
+// Sprite draws textured, animated frames; Rotation and AngularVelocity spin them.
+// Layer places an entity in a render layer and orders it by Z.
+// Camera views the world with pan, zoom and rotation.
 // Lifetime manages particle aging and automatic cleanup.
 // Mass enables gravitational interactions.
+// Target steers particles towards goal positions for morphing effects.
 // Particle, Emitter, and Attractor are tag components for entity classification.
 //
 // # Usage
@@ -29,16 +37,28 @@ package components
 
 // Component masks for efficient entity filtering using bitmasks.
 const (
-	MaskPosition     = uint64(1 << 0)
-	MaskVelocity     = uint64(1 << 1)
-	MaskAcceleration = uint64(1 << 2)
-	MaskColor        = uint64(1 << 3)
-	MaskLifetime     = uint64(1 << 4)
-	MaskMass         = uint64(1 << 5)
-	MaskSize         = uint64(1 << 6)
-	MaskEmitter      = uint64(1 << 7)
-	MaskAttractor    = uint64(1 << 8)
-	MaskParticle     = uint64(1 << 9)
+	MaskPosition        = uint64(1 << 0)
+	MaskVelocity        = uint64(1 << 1)
+	MaskAcceleration    = uint64(1 << 2)
+	MaskColor           = uint64(1 << 3)
+	MaskLifetime        = uint64(1 << 4)
+	MaskMass            = uint64(1 << 5)
+	MaskSize            = uint64(1 << 6)
+	MaskEmitter         = uint64(1 << 7)
+	MaskAttractor       = uint64(1 << 8)
+	MaskParticle        = uint64(1 << 9)
+	MaskTarget          = uint64(1 << 10)
+	MaskGradient        = uint64(1 << 11)
+	MaskSizeOverLife    = uint64(1 << 12)
+	MaskAlphaOverLife   = uint64(1 << 13)
+	MaskSpeedOverLife   = uint64(1 << 14)
+	MaskTrail           = uint64(1 << 15)
+	MaskBlend           = uint64(1 << 16)
+	MaskSprite          = uint64(1 << 17)
+	MaskRotation        = uint64(1 << 18)
+	MaskAngularVelocity = uint64(1 << 19)
+	MaskLayer           = uint64(1 << 20)
+	MaskCamera          = uint64(1 << 21)
 )
 
 // Composite masks for common component combinations.
@@ -64,6 +64,66 @@ func TestMaskParticle(t *testing.T) {
 	}
 }
 
+func TestMaskTarget(t *testing.T) {
+	if MaskTarget != uint64(1<<10) {
+		t.Errorf("MaskTarget = %v, want %v", MaskTarget, uint64(1<<10))
+	}
+}
+
+func TestMaskGradient(t *testing.T) {
+	if MaskGradient != uint64(1<<11) {
+		t.Errorf("MaskGradient = %v, want %v", MaskGradient, uint64(1<<11))
+	}
+}
+
+func TestMaskOverLife(t *testing.T) {
+	if MaskSizeOverLife != uint64(1<<12) {
+		t.Errorf("MaskSizeOverLife = %v, want %v", MaskSizeOverLife, uint64(1<<12))
+	}
+	if MaskAlphaOverLife != uint64(1<<13) {
+		t.Errorf("MaskAlphaOverLife = %v, want %v", MaskAlphaOverLife, uint64(1<<13))
+	}
+	if MaskSpeedOverLife != uint64(1<<14) {
+		t.Errorf("MaskSpeedOverLife = %v, want %v", MaskSpeedOverLife, uint64(1<<14))
+	}
+}
+
+func TestMaskTrail(t *testing.T) {
+	if MaskTrail != uint64(1<<15) {
+		t.Errorf("MaskTrail = %v, want %v", MaskTrail, uint64(1<<15))
+	}
+}
+
+func TestMaskBlend(t *testing.T) {
+	if MaskBlend != uint64(1<<16) {
+		t.Errorf("MaskBlend = %v, want %v", MaskBlend, uint64(1<<16))
+	}
+}
+
+func TestMaskSprite(t *testing.T) {
+	if MaskSprite != uint64(1<<17) {
+		t.Errorf("MaskSprite = %v, want %v", MaskSprite, uint64(1<<17))
+	}
+	if MaskRotation != uint64(1<<18) {
+		t.Errorf("MaskRotation = %v, want %v", MaskRotation, uint64(1<<18))
+	}
+	if MaskAngularVelocity != uint64(1<<19) {
+		t.Errorf("MaskAngularVelocity = %v, want %v", MaskAngularVelocity, uint64(1<<19))
+	}
+}
+
+func TestMaskLayer(t *testing.T) {
+	if MaskLayer != uint64(1<<20) {
+		t.Errorf("MaskLayer = %v, want %v", MaskLayer, uint64(1<<20))
+	}
+}
+
+func TestMaskCamera(t *testing.T) {
+	if MaskCamera != uint64(1<<21) {
+		t.Errorf("MaskCamera = %v, want %v", MaskCamera, uint64(1<<21))
+	}
+}
+
 func TestMaskMovable(t *testing.T) {
 	expected := MaskPosition | MaskVelocity
 	if MaskMovable != expected {
@@ -104,6 +164,18 @@ func TestNoMaskCollisions(t *testing.T) {
 		MaskEmitter,
 		MaskAttractor,
 		MaskParticle,
+		MaskTarget,
+		MaskGradient,
+		MaskSizeOverLife,
+		MaskAlphaOverLife,
+		MaskSpeedOverLife,
+		MaskTrail,
+		MaskBlend,
+		MaskSprite,
+		MaskRotation,
+		MaskAngularVelocity,
+		MaskLayer,
+		MaskCamera,
 	}
 
 	for i := 0; i < len(masks); i++ {
@@ -1,5 +1,10 @@
 package components
 
+import (
+	"strconv"
+	"sync/atomic"
+)
+
 // Particle is a tag component that identifies particle entities.
 // Tag components have no data - they exist purely to categorize entities
 // for efficient filtering in systems.
@@ -24,3 +29,13 @@ func (p *Particle) Mask() uint64 { return MaskParticle }
 
 // NewParticle creates a new Particle tag component.
 func NewParticle() *Particle { return &Particle{} }
+
+// particleIDs counts the Ids handed out by NewParticleID.
+var particleIDs atomic.Int64
+
+// NewParticleID returns an entity Id no other particle has. The ecs
+// entity manager removes entities by Id, so particles sharing an Id could
+// remove each other.
+func NewParticleID() string {
+	return "p-" + strconv.FormatInt(particleIDs.Add(1), 10)
+}
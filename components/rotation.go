@@ -0,0 +1,34 @@
+package components
+
+// Rotation is the orientation of an entity in degrees, clockwise.
+// RenderSystem rotates sprites by it; PhysicsSystem advances it by
+// AngularVelocity.
+type Rotation struct {
+	// Angle is the rotation in degrees.
+	Angle float32
+}
+
+// Mask returns the component mask for Rotation.
+func (r *Rotation) Mask() uint64 { return MaskRotation }
+
+// NewRotation creates a new Rotation at 0 degrees.
+func NewRotation() *Rotation { return &Rotation{} }
+
+// WithAngle sets the angle and returns the rotation for chaining.
+func (r *Rotation) WithAngle(deg float32) *Rotation { r.Angle = deg; return r }
+
+// AngularVelocity spins an entity: PhysicsSystem adds Speed × dt to its
+// Rotation every frame.
+type AngularVelocity struct {
+	// Speed is the rotation rate in degrees per second.
+	Speed float32
+}
+
+// Mask returns the component mask for AngularVelocity.
+func (a *AngularVelocity) Mask() uint64 { return MaskAngularVelocity }
+
+// NewAngularVelocity creates a new AngularVelocity at rest.
+func NewAngularVelocity() *AngularVelocity { return &AngularVelocity{} }
+
+// WithSpeed sets the rotation rate and returns the component for chaining.
+func (a *AngularVelocity) WithSpeed(deg float32) *AngularVelocity { a.Speed = deg; return a }
@@ -0,0 +1,23 @@
+package components
+
+import "testing"
+
+func TestRotation_Mask(t *testing.T) {
+	r := NewRotation().WithAngle(45)
+	if r.Mask() != MaskRotation {
+		t.Errorf("Rotation.Mask() = %v, want %v", r.Mask(), MaskRotation)
+	}
+	if r.Angle != 45 {
+		t.Errorf("Rotation.Angle = %v, want 45", r.Angle)
+	}
+}
+
+func TestAngularVelocity_Mask(t *testing.T) {
+	a := NewAngularVelocity().WithSpeed(90)
+	if a.Mask() != MaskAngularVelocity {
+		t.Errorf("AngularVelocity.Mask() = %v, want %v", a.Mask(), MaskAngularVelocity)
+	}
+	if a.Speed != 90 {
+		t.Errorf("AngularVelocity.Speed = %v, want 90", a.Speed)
+	}
+}
@@ -0,0 +1,21 @@
+package components
+
+import "github.com/deltatree/showcase/curves"
+
+// SizeOverLife scales an entity's radius by a curve over its lifetime.
+// ColorSystem sets Size.Radius = Size.StartSize * Curve.At(progress),
+// replacing the linear StartSize → EndSize interpolation.
+//
+// Example of a spark that pops up and then shrinks away:
+//
+//	sol := components.NewSizeOverLife(curves.Keys(0, 0.5, 0.1, 1.2, 1, 0))
+type SizeOverLife struct {
+	// Curve maps lifetime progress to a radius multiplier.
+	Curve curves.Curve
+}
+
+// Mask returns the component mask for SizeOverLife.
+func (s *SizeOverLife) Mask() uint64 { return MaskSizeOverLife }
+
+// NewSizeOverLife creates a SizeOverLife driven by curve.
+func NewSizeOverLife(curve curves.Curve) *SizeOverLife { return &SizeOverLife{Curve: curve} }
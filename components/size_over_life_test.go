@@ -0,0 +1,21 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/deltatree/showcase/curves"
+)
+
+func TestSizeOverLife_Mask(t *testing.T) {
+	c := NewSizeOverLife(curves.Linear)
+	if c.Mask() != MaskSizeOverLife {
+		t.Errorf("SizeOverLife.Mask() = %v, want %v", c.Mask(), MaskSizeOverLife)
+	}
+}
+
+func TestSizeOverLife_Curve(t *testing.T) {
+	c := NewSizeOverLife(curves.FadeOutQuad)
+	if got := c.Curve.At(0.5); got != 0.75 {
+		t.Errorf("SizeOverLife.Curve.At(0.5) = %v, want 0.75", got)
+	}
+}
@@ -0,0 +1,23 @@
+package components
+
+import "github.com/deltatree/showcase/curves"
+
+// SpeedOverLife scales how far an entity moves per frame by a curve over
+// its lifetime. PhysicsSystem multiplies the displacement (velocity * dt)
+// by Curve.At(progress); the velocity itself is left untouched, so forces
+// keep acting normally and the particle resumes full speed if the curve
+// rises again.
+//
+// Example of an ember that slows to a drift:
+//
+//	sol := components.NewSpeedOverLife(curves.Range(1, 0.1, curves.OutQuad))
+type SpeedOverLife struct {
+	// Curve maps lifetime progress to a speed multiplier.
+	Curve curves.Curve
+}
+
+// Mask returns the component mask for SpeedOverLife.
+func (s *SpeedOverLife) Mask() uint64 { return MaskSpeedOverLife }
+
+// NewSpeedOverLife creates a SpeedOverLife driven by curve.
+func NewSpeedOverLife(curve curves.Curve) *SpeedOverLife { return &SpeedOverLife{Curve: curve} }
@@ -0,0 +1,21 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/deltatree/showcase/curves"
+)
+
+func TestSpeedOverLife_Mask(t *testing.T) {
+	c := NewSpeedOverLife(curves.Linear)
+	if c.Mask() != MaskSpeedOverLife {
+		t.Errorf("SpeedOverLife.Mask() = %v, want %v", c.Mask(), MaskSpeedOverLife)
+	}
+}
+
+func TestSpeedOverLife_Curve(t *testing.T) {
+	c := NewSpeedOverLife(curves.FadeOutQuad)
+	if got := c.Curve.At(0.5); got != 0.75 {
+		t.Errorf("SpeedOverLife.Curve.At(0.5) = %v, want 0.75", got)
+	}
+}
@@ -0,0 +1,40 @@
+package components
+
+import "testing"
+
+func TestSprite_Mask(t *testing.T) {
+	s := NewSprite("spark")
+	if s.Mask() != MaskSprite {
+		t.Errorf("Sprite.Mask() = %v, want %v", s.Mask(), MaskSprite)
+	}
+	if s.Frames != 1 || s.Scale != 1 {
+		t.Errorf("NewSprite() Frames = %d, Scale = %v, want 1, 1", s.Frames, s.Scale)
+	}
+}
+
+func TestSprite_FrameAt(t *testing.T) {
+	static := NewSprite("snow").WithFrames(3, 1)
+	if got := static.FrameAt(10, 0.5); got != 3 {
+		t.Errorf("static FrameAt = %d, want 3", got)
+	}
+
+	looping := NewSprite("spark").WithFrames(4, 4).WithFPS(10)
+	tests := []struct {
+		age  float32
+		want int
+	}{
+		{0, 4}, {0.15, 5}, {0.35, 7}, {0.45, 4},
+	}
+	for _, tt := range tests {
+		if got := looping.FrameAt(tt.age, 0); got != tt.want {
+			t.Errorf("looping FrameAt(%v) = %d, want %d", tt.age, got, tt.want)
+		}
+	}
+
+	overLife := NewSprite("smoke").WithFrames(0, 4)
+	for progress, want := range map[float32]int{0: 0, 0.3: 1, 0.99: 3, 1: 3} {
+		if got := overLife.FrameAt(0, progress); got != want {
+			t.Errorf("over-life FrameAt(progress %v) = %d, want %d", progress, got, want)
+		}
+	}
+}
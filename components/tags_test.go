@@ -18,6 +18,17 @@ func TestParticle_NewParticle(t *testing.T) {
 	}
 }
 
+func TestNewParticleID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := NewParticleID()
+		if seen[id] {
+			t.Fatalf("NewParticleID() returned %q twice", id)
+		}
+		seen[id] = true
+	}
+}
+
 func TestAttractor_Mask(t *testing.T) {
 	a := NewAttractor()
 	if a.Mask() != MaskAttractor {
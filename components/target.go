@@ -0,0 +1,37 @@
+package components
+
+// Target gives an entity a goal position it steers towards.
+// TargetSystem turns the distance to the goal into a spring-like
+// acceleration, so particles fly in, overshoot slightly and settle on
+// their target. Used for morphing particles into shapes, logos and text.
+//
+// While a target is Active the entity does not age, so a figure can be
+// held on screen for as long as needed. Releasing the target (setting
+// Active to false or removing the component) returns the particle to
+// normal physics and lifetime handling.
+//
+// Example steering a particle towards (400, 300):
+//
+//	target := components.NewTarget().With(400, 300).WithStrength(8)
+type Target struct {
+	// X is the horizontal goal position in pixels.
+	X float32
+	// Y is the vertical goal position in pixels.
+	Y float32
+	// Strength is the steering rate in 1/s; higher values arrive faster.
+	Strength float32
+	// Active enables steering towards the target.
+	Active bool
+}
+
+// Mask returns the component mask for Target.
+func (t *Target) Mask() uint64 { return MaskTarget }
+
+// NewTarget creates a new active Target with a default strength of 4.
+func NewTarget() *Target { return &Target{Strength: 4.0, Active: true} }
+
+// With sets the goal position and returns the target for chaining.
+func (t *Target) With(x, y float32) *Target { t.X = x; t.Y = y; return t }
+
+// WithStrength sets the steering strength and returns the target for chaining.
+func (t *Target) WithStrength(s float32) *Target { t.Strength = s; return t }
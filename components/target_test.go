@@ -0,0 +1,29 @@
+package components
+
+import (
+	"testing"
+)
+
+func TestTarget_Mask(t *testing.T) {
+	tg := NewTarget()
+	if tg.Mask() != MaskTarget {
+		t.Errorf("Target.Mask() = %v, want %v", tg.Mask(), MaskTarget)
+	}
+}
+
+func TestTarget_NewTarget(t *testing.T) {
+	tg := NewTarget()
+	if !tg.Active {
+		t.Error("NewTarget().Active = false, want true")
+	}
+	if tg.Strength != 4.0 {
+		t.Errorf("NewTarget().Strength = %v, want 4.0", tg.Strength)
+	}
+}
+
+func TestTarget_Chaining(t *testing.T) {
+	tg := NewTarget().With(10, 20).WithStrength(8)
+	if tg.X != 10 || tg.Y != 20 || tg.Strength != 8 {
+		t.Errorf("Target chaining failed: got (%v, %v, %v), want (10, 20, 8)", tg.X, tg.Y, tg.Strength)
+	}
+}
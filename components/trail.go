@@ -0,0 +1,69 @@
+package components
+
+// Trail remembers an entity's most recent positions in a fixed-size ring
+// buffer. PhysicsSystem pushes the position before every step and
+// TrailRenderer draws the history as fading segments behind the entity.
+//
+// Example of a trail of the last five positions:
+//
+//	trail := components.NewTrail(5)
+type Trail struct {
+	xs, ys []float32
+	head   int
+	count  int
+}
+
+// Mask returns the component mask for Trail.
+func (t *Trail) Mask() uint64 { return MaskTrail }
+
+// NewTrail creates an empty trail holding up to length positions.
+func NewTrail(length int) *Trail {
+	t := &Trail{}
+	t.Resize(length)
+	return t
+}
+
+// Push records a position, overwriting the oldest one when the trail is
+// full.
+func (t *Trail) Push(x, y float32) {
+	n := len(t.xs)
+	if n == 0 {
+		return
+	}
+	t.head = (t.head + 1) % n
+	t.xs[t.head], t.ys[t.head] = x, y
+	if t.count < n {
+		t.count++
+	}
+}
+
+// At returns the i-th recorded position, where 0 is the newest and
+// Len()-1 the oldest.
+func (t *Trail) At(i int) (x, y float32) {
+	n := len(t.xs)
+	j := (t.head - i + n) % n
+	return t.xs[j], t.ys[j]
+}
+
+// Len returns the number of recorded positions.
+func (t *Trail) Len() int { return t.count }
+
+// Cap returns the maximum number of positions the trail holds.
+func (t *Trail) Cap() int { return len(t.xs) }
+
+// Reset forgets all recorded positions, e.g. after the entity jumped.
+func (t *Trail) Reset() {
+	t.head, t.count = 0, 0
+}
+
+// Resize changes the trail length and forgets all recorded positions.
+// The buffer is reused when it is large enough.
+func (t *Trail) Resize(length int) {
+	length = max(length, 0)
+	if cap(t.xs) >= length {
+		t.xs, t.ys = t.xs[:length], t.ys[:length]
+	} else {
+		t.xs, t.ys = make([]float32, length), make([]float32, length)
+	}
+	t.Reset()
+}
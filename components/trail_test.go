@@ -0,0 +1,39 @@
+package components
+
+import "testing"
+
+func TestTrail_Mask(t *testing.T) {
+	c := NewTrail(3)
+	if c.Mask() != MaskTrail {
+		t.Errorf("Trail.Mask() = %v, want %v", c.Mask(), MaskTrail)
+	}
+}
+
+func TestTrail_Push(t *testing.T) {
+	c := NewTrail(3)
+	for i := 1; i <= 5; i++ {
+		c.Push(float32(i), float32(-i))
+	}
+	if c.Len() != 3 {
+		t.Fatalf("Trail.Len() = %d, want 3", c.Len())
+	}
+	for i, want := range []float32{5, 4, 3} {
+		if x, y := c.At(i); x != want || y != -want {
+			t.Errorf("Trail.At(%d) = (%v, %v), want (%v, %v)", i, x, y, want, -want)
+		}
+	}
+}
+
+func TestTrail_Resize(t *testing.T) {
+	c := NewTrail(5)
+	c.Push(1, 1)
+	c.Resize(2)
+	if c.Len() != 0 || c.Cap() != 2 {
+		t.Errorf("after Resize(2): Len() = %d, Cap() = %d, want 0, 2", c.Len(), c.Cap())
+	}
+	c.Resize(0)
+	c.Push(1, 1)
+	if c.Len() != 0 {
+		t.Errorf("zero-length trail should not record, Len() = %d", c.Len())
+	}
+}
@@ -0,0 +1,73 @@
+package curves
+
+// Bezier is a cubic Bezier easing from (0, 0) to (1, 1) with control
+// points (X1, Y1) and (X2, Y2), matching CSS cubic-bezier(). X1 and X2
+// must lie in [0, 1]; Y values may overshoot for anticipation effects.
+type Bezier struct {
+	X1, Y1, X2, Y2 float32
+}
+
+// NewBezier creates a cubic Bezier easing.
+//
+// Example of the CSS "ease" timing function:
+//
+//	ease := curves.NewBezier(0.25, 0.1, 0.25, 1)
+func NewBezier(x1, y1, x2, y2 float32) Bezier {
+	return Bezier{X1: min(max(x1, 0), 1), Y1: y1, X2: min(max(x2, 0), 1), Y2: y2}
+}
+
+// At returns the curve's y for x = t.
+func (b Bezier) At(t float32) float32 {
+	if t <= 0 {
+		return 0
+	}
+	if t >= 1 {
+		return 1
+	}
+	return bezier(b.Y1, b.Y2, b.solve(t))
+}
+
+// solve finds the curve parameter u with x(u) = x, using Newton's method
+// with a bisection fallback.
+func (b Bezier) solve(x float32) float32 {
+	u := x
+	for i := 0; i < 8; i++ {
+		dx := bezier(b.X1, b.X2, u) - x
+		if dx > -1e-6 && dx < 1e-6 {
+			return u
+		}
+		d := bezierSlope(b.X1, b.X2, u)
+		if d > -1e-6 && d < 1e-6 {
+			break
+		}
+		u -= dx / d
+	}
+
+	lo, hi := float32(0), float32(1)
+	u = x
+	for i := 0; i < 32; i++ {
+		v := bezier(b.X1, b.X2, u)
+		if v > x-1e-6 && v < x+1e-6 {
+			break
+		}
+		if v < x {
+			lo = u
+		} else {
+			hi = u
+		}
+		u = (lo + hi) / 2
+	}
+	return u
+}
+
+// bezier evaluates one axis of a cubic Bezier with end points 0 and 1.
+func bezier(p1, p2, u float32) float32 {
+	v := 1 - u
+	return 3*v*v*u*p1 + 3*v*u*u*p2 + u*u*u
+}
+
+// bezierSlope is the derivative of bezier with respect to u.
+func bezierSlope(p1, p2, u float32) float32 {
+	v := 1 - u
+	return 3*v*v*p1 + 6*v*u*(p2-p1) + 3*u*u*(1-p2)
+}
@@ -0,0 +1,109 @@
+// Package curves provides easing functions and curves that map a
+// normalized time t in [0, 1] to a value, used to animate particle
+// properties over their lifetime.
+//
+// Three kinds of curves are available:
+//   - Easing families (Quad, Cubic, Quart, Sine, Expo, Back) in In, Out
+//     and InOut variants, plus SmoothStep
+//   - Cubic Bezier curves with CSS cubic-bezier() semantics
+//   - Piecewise-linear Keyframes
+//
+// # Usage
+//
+// Shrink a particle with a fast start and soft landing:
+//
+//	size := components.NewSizeOverLife(curves.Reverse(curves.OutCubic))
+//
+// Fade in quickly, hold, then fade out:
+//
+//	alpha := components.NewAlphaOverLife(curves.Keys(0, 0, 0.1, 1, 0.7, 1, 1, 0))
+package curves
+
+import "math"
+
+// Curve maps t in [0, 1] to a value, usually also in [0, 1].
+type Curve interface {
+	At(t float32) float32
+}
+
+// Func adapts an ordinary function to the Curve interface.
+type Func func(t float32) float32
+
+// At calls f(t).
+func (f Func) At(t float32) float32 { return f(t) }
+
+// Easing functions. In variants start slowly, Out variants end slowly and
+// InOut variants do both.
+var (
+	Linear = Func(func(t float32) float32 { return t })
+
+	InQuad    = Func(func(t float32) float32 { return t * t })
+	OutQuad   = Func(func(t float32) float32 { return 1 - (1-t)*(1-t) })
+	InOutQuad = inOut(InQuad)
+
+	InCubic    = Func(func(t float32) float32 { return t * t * t })
+	OutCubic   = out(InCubic)
+	InOutCubic = inOut(InCubic)
+
+	InQuart    = Func(func(t float32) float32 { return t * t * t * t })
+	OutQuart   = out(InQuart)
+	InOutQuart = inOut(InQuart)
+
+	InSine    = Func(func(t float32) float32 { return 1 - float32(math.Cos(float64(t)*math.Pi/2)) })
+	OutSine   = Func(func(t float32) float32 { return float32(math.Sin(float64(t) * math.Pi / 2)) })
+	InOutSine = Func(func(t float32) float32 { return 0.5 - 0.5*float32(math.Cos(float64(t)*math.Pi)) })
+
+	InExpo = Func(func(t float32) float32 {
+		if t <= 0 {
+			return 0
+		}
+		return float32(math.Pow(2, 10*float64(t)-10))
+	})
+	OutExpo   = out(InExpo)
+	InOutExpo = inOut(InExpo)
+
+	// InBack pulls back slightly below zero before accelerating.
+	InBack = Func(func(t float32) float32 {
+		const c1 = 1.70158
+		return (c1+1)*t*t*t - c1*t*t
+	})
+	OutBack   = out(InBack)
+	InOutBack = inOut(InBack)
+
+	SmoothStep = Func(func(t float32) float32 { return t * t * (3 - 2*t) })
+
+	// FadeOutQuad falls from 1 to 0 as 1 - t², holding full value early in
+	// the lifetime.
+	FadeOutQuad = Func(func(t float32) float32 { return 1 - t*t })
+)
+
+// out mirrors an In easing into its Out variant.
+func out(in Curve) Func {
+	return func(t float32) float32 { return 1 - in.At(1-t) }
+}
+
+// inOut combines an In easing and its mirror into an InOut variant.
+func inOut(in Curve) Func {
+	return func(t float32) float32 {
+		if t < 0.5 {
+			return in.At(2*t) / 2
+		}
+		return 1 - in.At(2-2*t)/2
+	}
+}
+
+// Reverse returns a curve running c backwards: 1 - c(t). Useful to turn a
+// rising ease into a falling one.
+func Reverse(c Curve) Curve {
+	return Func(func(t float32) float32 { return 1 - c.At(t) })
+}
+
+// Range maps the output of c from [0, 1] to [from, to].
+func Range(from, to float32, c Curve) Curve {
+	return Func(func(t float32) float32 { return from + (to-from)*c.At(t) })
+}
+
+// Constant returns a curve that is v everywhere.
+func Constant(v float32) Curve {
+	return Func(func(float32) float32 { return v })
+}
@@ -0,0 +1,87 @@
+package curves
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float32) bool { return math.Abs(float64(a-b)) < 1e-3 }
+
+func TestEasings_Endpoints(t *testing.T) {
+	easings := map[string]Curve{
+		"Linear": Linear, "SmoothStep": SmoothStep,
+		"InQuad": InQuad, "OutQuad": OutQuad, "InOutQuad": InOutQuad,
+		"InCubic": InCubic, "OutCubic": OutCubic, "InOutCubic": InOutCubic,
+		"InQuart": InQuart, "OutQuart": OutQuart, "InOutQuart": InOutQuart,
+		"InSine": InSine, "OutSine": OutSine, "InOutSine": InOutSine,
+		"InExpo": InExpo, "OutExpo": OutExpo, "InOutExpo": InOutExpo,
+		"InBack": InBack, "OutBack": OutBack, "InOutBack": InOutBack,
+	}
+	for name, c := range easings {
+		if got := c.At(0); !near(got, 0) {
+			t.Errorf("%s.At(0) = %v, want 0", name, got)
+		}
+		if got := c.At(1); !near(got, 1) {
+			t.Errorf("%s.At(1) = %v, want 1", name, got)
+		}
+	}
+}
+
+func TestEasings_Shape(t *testing.T) {
+	if InQuad.At(0.5) >= 0.5 {
+		t.Error("InQuad should start slowly")
+	}
+	if OutQuad.At(0.5) <= 0.5 {
+		t.Error("OutQuad should start quickly")
+	}
+	if !near(InOutCubic.At(0.5), 0.5) {
+		t.Errorf("InOutCubic.At(0.5) = %v, want 0.5", InOutCubic.At(0.5))
+	}
+	if InBack.At(0.2) >= 0 {
+		t.Error("InBack should dip below zero")
+	}
+	if !near(FadeOutQuad.At(0.5), 0.75) {
+		t.Errorf("FadeOutQuad.At(0.5) = %v, want 0.75", FadeOutQuad.At(0.5))
+	}
+}
+
+func TestBezier(t *testing.T) {
+	linear := NewBezier(0.25, 0.25, 0.75, 0.75)
+	for _, x := range []float32{0, 0.1, 0.5, 0.9, 1} {
+		if got := linear.At(x); !near(got, x) {
+			t.Errorf("linear bezier At(%v) = %v", x, got)
+		}
+	}
+
+	ease := NewBezier(0.25, 0.1, 0.25, 1)
+	if got := ease.At(0.5); !near(got, 0.8024) {
+		t.Errorf("ease At(0.5) = %v, want ~0.8024", got)
+	}
+}
+
+func TestKeyframes(t *testing.T) {
+	k := Keys(1, 0, 0, 0, 0.5, 1)
+	tests := []struct{ t, want float32 }{
+		{-1, 0}, {0, 0}, {0.25, 0.5}, {0.5, 1}, {0.75, 0.5}, {1, 0}, {2, 0},
+	}
+	for _, tt := range tests {
+		if got := k.At(tt.t); !near(got, tt.want) {
+			t.Errorf("At(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+	if got := Keys().At(0.5); got != 0 {
+		t.Errorf("empty keyframes At = %v, want 0", got)
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	if got := Reverse(Linear).At(0.25); !near(got, 0.75) {
+		t.Errorf("Reverse(Linear).At(0.25) = %v, want 0.75", got)
+	}
+	if got := Range(10, 20, Linear).At(0.5); !near(got, 15) {
+		t.Errorf("Range(10, 20).At(0.5) = %v, want 15", got)
+	}
+	if got := Constant(3).At(0.9); got != 3 {
+		t.Errorf("Constant(3).At = %v, want 3", got)
+	}
+}
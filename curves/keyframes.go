@@ -0,0 +1,44 @@
+package curves
+
+import "sort"
+
+// Keyframe is one point of a Keyframes curve.
+type Keyframe struct {
+	T, V float32
+}
+
+// Keyframes is a piecewise-linear curve through keyframes sorted by T.
+// Before the first keyframe its value is held, likewise after the last.
+type Keyframes []Keyframe
+
+// Keys builds Keyframes from alternating time and value arguments and
+// sorts them by time. A trailing unpaired argument is ignored.
+//
+// Example rising to 1 at 10% of the lifetime and back to 0 at the end:
+//
+//	curves.Keys(0, 0, 0.1, 1, 1, 0)
+func Keys(tv ...float32) Keyframes {
+	k := make(Keyframes, 0, len(tv)/2)
+	for i := 0; i+1 < len(tv); i += 2 {
+		k = append(k, Keyframe{T: tv[i], V: tv[i+1]})
+	}
+	sort.SliceStable(k, func(i, j int) bool { return k[i].T < k[j].T })
+	return k
+}
+
+// At returns the interpolated value at t. An empty curve returns 0.
+func (k Keyframes) At(t float32) float32 {
+	n := len(k)
+	if n == 0 {
+		return 0
+	}
+	if t <= k[0].T {
+		return k[0].V
+	}
+	if t >= k[n-1].T {
+		return k[n-1].V
+	}
+	i := sort.Search(n, func(i int) bool { return k[i].T > t }) - 1
+	from, to := k[i], k[i+1]
+	return from.V + (to.V-from.V)*(t-from.T)/(to.T-from.T)
+}
@@ -2,7 +2,7 @@
 //
 // Configuration can be loaded from a JSON file or use sensible defaults.
 // The Config struct contains all tunable parameters organized into logical groups:
-// Window, Particles, and Physics.
+// Window, Particles, Physics, Performance, and Render.
 //
 // # Loading Configuration
 //
@@ -20,7 +20,10 @@
 //	{
 //	    "window": { "width": 1280, "height": 720, "title": "Particle Symphony" },
 //	    "particles": { "maxCount": 10000, "spawnRate": 100 },
-//	    "physics": { "damping": 0.99, "maxVelocity": 500 }
+//	    "physics": { "damping": 0.99, "maxVelocity": 500 },
+//	    "performance": { "particleStore": false, "parallel": true },
+//	    "render": { "trails": true, "glow": true, "motionBlur": true, "bloom": true, "postFX": true,
+//	                "lighting": true, "batched": true, "juice": "normal" }
 //	}
 package config
 
@@ -31,9 +34,11 @@ import (
 
 // Config holds all configuration for Particle Symphony.
 type Config struct {
-	Window    WindowConfig   `json:"window"`
-	Particles ParticleConfig `json:"particles"`
-	Physics   PhysicsConfig  `json:"physics"`
+	Window      WindowConfig      `json:"window"`
+	Particles   ParticleConfig    `json:"particles"`
+	Physics     PhysicsConfig     `json:"physics"`
+	Performance PerformanceConfig `json:"performance"`
+	Render      RenderConfig      `json:"render"`
 }
 
 // WindowConfig holds window-related settings.
@@ -59,6 +64,39 @@ type PhysicsConfig struct {
 	MaxVelocity float32 `json:"maxVelocity"`
 }
 
+// PerformanceConfig holds settings that trade features for speed.
+type PerformanceConfig struct {
+	// ParticleStore spawns emitter particles into a struct-of-arrays
+	// store instead of ECS entities. Morph targets do not apply to
+	// store particles.
+	ParticleStore bool `json:"particleStore"`
+	// Parallel spreads gravity, physics and color updates over one
+	// worker per CPU.
+	Parallel bool `json:"parallel"`
+}
+
+// RenderConfig selects the particle render passes. Each enabled pass is
+// still subject to the active quality level.
+type RenderConfig struct {
+	Trails     bool `json:"trails"`
+	Glow       bool `json:"glow"`
+	MotionBlur bool `json:"motionBlur"`
+	// Bloom post-processes particles with a GPU bloom shader chain,
+	// replacing the per-particle glow.
+	Bloom bool `json:"bloom"`
+	// PostFX applies the preset's post-process effect stack (vignette,
+	// chromatic aberration, film grain, CRT) to the final frame.
+	PostFX bool `json:"postFX"`
+	// Lighting lets presets light the scenery with their particles.
+	Lighting bool `json:"lighting"`
+	// Juice is the strength of screen feedback on events: "off",
+	// "subtle", "normal" or "intense". The J key cycles it.
+	Juice string `json:"juice"`
+	// Batched draws all particles of a texture with one draw call
+	// instead of one raylib call per shape.
+	Batched bool `json:"batched"`
+}
+
 // Default returns sensible default configuration.
 func Default() *Config {
 	return &Config{
@@ -79,6 +117,19 @@ func Default() *Config {
 			Damping:     0.99,
 			MaxVelocity: 500.0,
 		},
+		Performance: PerformanceConfig{
+			Parallel: true,
+		},
+		Render: RenderConfig{
+			Trails:     true,
+			Glow:       true,
+			MotionBlur: true,
+			Bloom:      true,
+			PostFX:     true,
+			Lighting:   true,
+			Juice:      "normal",
+			Batched:    true,
+		},
 	}
 }
 
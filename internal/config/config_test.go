@@ -25,6 +25,18 @@ func TestDefault(t *testing.T) {
 	if cfg.Particles.MaxCount != 10000 {
 		t.Errorf("Default().Particles.MaxCount = %v, want 10000", cfg.Particles.MaxCount)
 	}
+	if !cfg.Performance.Parallel {
+		t.Error("Default().Performance.Parallel = false, want true")
+	}
+	if !cfg.Render.Trails || !cfg.Render.Glow || !cfg.Render.MotionBlur || !cfg.Render.Bloom || !cfg.Render.PostFX || !cfg.Render.Lighting {
+		t.Errorf("Default().Render = %+v, want all passes enabled", cfg.Render)
+	}
+	if !cfg.Render.Batched {
+		t.Error("Default().Render.Batched = false, want true")
+	}
+	if cfg.Render.Juice != "normal" {
+		t.Errorf("Default().Render.Juice = %q, want normal", cfg.Render.Juice)
+	}
 }
 
 func TestLoad_DefaultOnMissingFile(t *testing.T) {
@@ -64,6 +76,12 @@ func TestLoad_ValidConfig(t *testing.T) {
 			Damping:     0.98,
 			MaxVelocity: 1000.0,
 		},
+		Performance: PerformanceConfig{
+			ParticleStore: true,
+		},
+		Render: RenderConfig{
+			Glow: true,
+		},
 	}
 
 	data, err := json.Marshal(testCfg)
@@ -100,6 +118,12 @@ func TestLoad_ValidConfig(t *testing.T) {
 	if cfg.Physics.Gravity != 500.0 {
 		t.Errorf("Load().Physics.Gravity = %v, want 500.0", cfg.Physics.Gravity)
 	}
+	if !cfg.Performance.ParticleStore {
+		t.Error("Load().Performance.ParticleStore = false, want true")
+	}
+	if cfg.Render.Trails || !cfg.Render.Glow || cfg.Render.MotionBlur {
+		t.Errorf("Load().Render = %+v, want only glow", cfg.Render)
+	}
 }
 
 func TestLoad_InvalidJSON(t *testing.T) {
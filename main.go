@@ -10,7 +10,10 @@
 //   - Right Click: Repel particles
 //   - Double-Click: Lock attract/repel mode
 //   - 1-5: Switch between presets
+//   - T: Form / release a morph figure
+//   - Wheel: Zoom, Middle Drag / Space+Drag: Pan, C: Reset camera
 //   - F3: Toggle debug overlay
+//   - F4: Toggle particle trails
 //
 // Run: go run main.go
 package main
@@ -19,9 +22,14 @@ import (
 	"log"
 
 	"github.com/andygeiss/ecs"
+	"github.com/deltatree/showcase/components"
+	"github.com/deltatree/showcase/curves"
 	"github.com/deltatree/showcase/internal/config"
+	"github.com/deltatree/showcase/premium"
 	"github.com/deltatree/showcase/presets"
+	"github.com/deltatree/showcase/shapes"
 	"github.com/deltatree/showcase/systems"
+	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 func main() {
@@ -33,7 +41,7 @@ func main() {
 	}
 
 	// Initialize ECS managers
-	em := ecs.NewEntityManager()
+	em := systems.NewQueryCache()
 	sm := ecs.NewSystemManager()
 
 	// Create systems
@@ -44,6 +52,13 @@ func main() {
 		float32(cfg.Window.Height),
 	)
 
+	// Structural changes are deferred to a single flush point per frame;
+	// expired particles are recycled into the emitter's pool on flush
+	commands := systems.NewCommandBuffer()
+	commands.SetPool(emitterSystem.GetPool())
+	lifetimeSystem := systems.NewLifetimeSystem()
+	lifetimeSystem.SetCommands(commands)
+
 	renderSystem := systems.NewRenderSystem(
 		cfg.Window.Width,
 		cfg.Window.Height,
@@ -56,13 +71,62 @@ func main() {
 		emitterSystem.SetMaxParticles(newMax)
 	})
 
+	// Built-in sprite sheets for Sprite components
+	for _, a := range presets.SpriteAtlases() {
+		renderSystem.AddSpriteSheet(a.Name, systems.NewSpriteSheet(a.Image, a.FrameWidth, a.FrameHeight))
+	}
+
+	// Render passes from config; the quality level tunes each pass
+	var passes systems.RenderPass
+	if cfg.Render.Trails {
+		passes |= systems.PassTrails
+	}
+	if cfg.Render.Glow {
+		passes |= systems.PassGlow
+	}
+	if cfg.Render.MotionBlur {
+		passes |= systems.PassMotionBlur
+	}
+	if cfg.Render.Bloom {
+		passes |= systems.PassBloom
+	}
+	if cfg.Render.PostFX {
+		passes |= systems.PassPostFX
+	}
+	if cfg.Render.Lighting {
+		passes |= systems.PassLighting
+	}
+	renderSystem.SetPasses(passes)
+	renderSystem.SetBatched(cfg.Render.Batched)
+
+	// Keep the emitter's quality (particle limit, trail length) in sync
+	renderSystem.SetOnQualityChange(func(level premium.QualityLevel) {
+		emitterSystem.SetQuality(level)
+	})
+
+	// Screen feedback on events, scaled by the configured juice level
+	juice := systems.NewJuiceSystem(renderSystem, premium.ParseJuiceLevel(cfg.Render.Juice))
+	renderSystem.SetJuiceLevel(juice.Level())
+	juice.SetOnLevelChange(renderSystem.SetJuiceLevel)
+	lifetimeSystem.SetOnExpire(juice.MassDeath)
+
+	// Optional struct-of-arrays backend for emitter particles
+	var store *systems.ParticleStore
+	if cfg.Performance.ParticleStore {
+		store = systems.NewParticleStore(cfg.Particles.MaxCount)
+	}
+
 	// Preset switcher function
 	currentPresetIndex := 0
 	presetSwitcher := func(index int) {
 		currentPresetIndex = index
+		if store != nil {
+			store.Clear()
+		}
 		preset := presets.GetPreset(index)
-		preset.Apply(em, cfg)
+		preset.Apply(commands.Deferred(em), cfg)
 		renderSystem.SetPresetName(preset.Name())
+		juice.Trigger(systems.JuicePresetSwitch, 0, 0)
 
 		// Update emitter based on preset
 		type presetWithConfig interface {
@@ -74,26 +138,177 @@ func main() {
 			emitterSystem.SetSpawnPattern(pattern)
 			emitterSystem.SetSpawnRate(rate)
 		}
+
+		// Emitted particles interpolate in the preset palette's color
+		// space; the palette also tints the glow pass
+		type presetWithPalette interface {
+			Palette() premium.ColorPalette
+		}
+		if p, ok := preset.(presetWithPalette); ok {
+			emitterSystem.SetColorSpace(p.Palette().Space)
+			renderSystem.SetPalette(p.Palette())
+			juice.SetPalette(p.Palette())
+		}
+
+		// Presets may blend their particles additively or otherwise
+		type presetWithBlend interface {
+			BlendMode() components.BlendMode
+		}
+		if p, ok := preset.(presetWithBlend); ok {
+			renderSystem.SetBlendMode(p.BlendMode())
+		} else {
+			renderSystem.SetBlendMode(components.BlendAlpha)
+		}
+
+		// Presets may draw a gradient, starfield or image behind their
+		// particles, optionally keeping light trails of earlier frames
+		type presetWithBackground interface {
+			Background() premium.Background
+		}
+		if p, ok := preset.(presetWithBackground); ok {
+			renderSystem.SetBackground(p.Background())
+		} else {
+			renderSystem.SetBackground(premium.DefaultBackground)
+		}
+
+		// Presets may post-process the final frame with a stack of
+		// screen effects
+		type presetWithPostEffects interface {
+			PostEffects() []premium.PostEffect
+		}
+		if p, ok := preset.(presetWithPostEffects); ok {
+			renderSystem.SetPostEffects(p.PostEffects())
+		} else {
+			renderSystem.SetPostEffects(nil)
+		}
+
+		// Presets may animate emitted particles along curves
+		type presetWithCurves interface {
+			OverLifeConfig() (size, alpha, speed curves.Curve)
+		}
+		if p, ok := preset.(presetWithCurves); ok {
+			emitterSystem.SetOverLifeCurves(p.OverLifeConfig())
+		} else {
+			emitterSystem.SetOverLifeCurves(nil, nil, nil)
+		}
+
+		// Presets may draw emitted particles as sprites
+		type presetWithSprite interface {
+			SpriteConfig() (*components.Sprite, float32)
+		}
+		if p, ok := preset.(presetWithSprite); ok {
+			emitterSystem.SetSprite(p.SpriteConfig())
+		} else {
+			emitterSystem.SetSprite(nil, 0)
+		}
+
+		// Presets may draw their particles as merging liquid blobs
+		type presetWithMetaballs interface {
+			MetaballConfig() *premium.Metaballs
+		}
+		if p, ok := preset.(presetWithMetaballs); ok {
+			renderSystem.SetMetaballs(p.MetaballConfig())
+		} else {
+			renderSystem.SetMetaballs(nil)
+		}
+
+		// Presets may light the scenery with their particles
+		type presetWithLighting interface {
+			LightingConfig() *premium.Lighting
+		}
+		if p, ok := preset.(presetWithLighting); ok {
+			renderSystem.SetLighting(p.LightingConfig())
+		} else {
+			renderSystem.SetLighting(nil)
+		}
+
+		// Presets may color emitted particles with a multi-stop gradient
+		type presetWithGradient interface {
+			GradientConfig() *components.Gradient
+		}
+		if p, ok := preset.(presetWithGradient); ok {
+			emitterSystem.SetGradient(p.GradientConfig())
+		} else {
+			emitterSystem.SetGradient(nil)
+		}
 	}
 
+	// Morph toggle: particles assemble into the title, then dissolve
+	inputSystem := systems.NewInputSystem(presetSwitcher)
+	inputSystem.SetOnMorph(func() {
+		if em.Count(components.MaskTarget) > 0 {
+			presets.ReleaseTargets(em, 400)
+			return
+		}
+		w, h := float32(rl.GetScreenWidth()), float32(rl.GetScreenHeight())
+		points := textPoints("ECS", 200, 6).Scaled(1.5).Centered(w/2, h/2)
+		presets.AssignTargets(em, points, 6)
+	})
+	inputSystem.SetOnAttractLock(func(x, y float32) {
+		juice.Trigger(systems.JuiceAttractLock, x, y)
+	})
+	inputSystem.SetOnRepelRelease(func(x, y float32) {
+		juice.Trigger(systems.JuiceRepelRelease, x, y)
+	})
+
+	physicsSystem := systems.NewPhysicsSystem(
+		cfg.Physics.Damping,
+		cfg.Physics.MaxVelocity,
+		float32(cfg.Window.Width),
+		float32(cfg.Window.Height),
+	)
+
+	// The camera starts centered on the window at zoom 1
+	camera := components.NewCamera().With(float32(cfg.Window.Width)/2, float32(cfg.Window.Height)/2)
+	em.Add(ecs.NewEntity("camera", []ecs.Component{camera}))
+
+	// A resized window enlarges the world: particles wrap and spawn in the
+	// new bounds, the current preset moves its layout into it and the
+	// camera keeps the world point at the top left corner in place
+	renderSystem.SetOnResize(func(width, height int32) {
+		fromW, fromH := float32(cfg.Window.Width), float32(cfg.Window.Height)
+		toW, toH := float32(width), float32(height)
+		x0, y0 := camera.ScreenToWorld(0, 0, fromW, fromH)
+		x1, y1 := camera.ScreenToWorld(0, 0, toW, toH)
+		camera.X += x0 - x1
+		camera.Y += y0 - y1
+		cfg.Window.Width, cfg.Window.Height = width, height
+		physicsSystem.SetBounds(toW, toH)
+		emitterSystem.SetBounds(toW, toH)
+
+		type presetWithResize interface {
+			Resize(em ecs.EntityManager, fromW, fromH, toW, toH float32)
+		}
+		if p, ok := presets.GetPreset(currentPresetIndex).(presetWithResize); ok {
+			p.Resize(em, fromW, fromH, toW, toH)
+		}
+	})
+
 	// Register systems in correct order
-	sm.Add(
-		systems.NewInputSystem(presetSwitcher),
+	pipeline := []ecs.System{
+		inputSystem,
 		emitterSystem,
 		systems.NewGravitySystem(),
-		systems.NewPhysicsSystem(
-			cfg.Physics.Damping,
-			cfg.Physics.MaxVelocity,
-			float32(cfg.Window.Width),
-			float32(cfg.Window.Height),
-		),
-		systems.NewLifetimeSystem(),
+		systems.NewTargetSystem(),
+		physicsSystem,
+		systems.NewCameraSystem(),
+		lifetimeSystem,
+		juice,
+		systems.NewCommandFlushSystem(commands),
 		systems.NewColorSystem(),
 		renderSystem,
-	)
+	}
+	if store != nil {
+		systems.AttachStore(store, pipeline...)
+	}
+	if cfg.Performance.Parallel {
+		workers := systems.NewWorkerPool(0)
+		defer workers.Close()
+		systems.AttachWorkers(workers, pipeline...)
+	}
+	sm.Add(pipeline...)
 
 	// Apply default preset
-	_ = currentPresetIndex
 	presetSwitcher(0)
 
 	// Create and run engine
@@ -102,3 +317,21 @@ func main() {
 	defer engine.Teardown()
 	engine.Run()
 }
+
+// textPoints rasterizes text with raylib's default font and samples the
+// glyph pixels with shapes.FromImage. The default font is loaded by
+// rl.InitWindow, so textPoints must be called after the RenderSystem has
+// been set up.
+func textPoints(text string, fontSize int32, step int) shapes.PointSet {
+	// ImageText returns a pointer with cgo and a value with purego
+	// (Windows), so the image is unloaded through whichever it is
+	var img *rl.Image
+	switch v := any(rl.ImageText(text, fontSize, rl.White)).(type) {
+	case *rl.Image:
+		img = v
+	case rl.Image:
+		img = &v
+	}
+	defer rl.UnloadImage(img)
+	return shapes.FromImage(img.ToImage(), step, 128)
+}
@@ -0,0 +1,104 @@
+package premium
+
+// BackgroundKind selects what is drawn behind the particles.
+type BackgroundKind int
+
+const (
+	// BackgroundSolid fills the screen with the top color.
+	BackgroundSolid BackgroundKind = iota
+	// BackgroundGradient blends vertically from the top to the bottom color.
+	BackgroundGradient
+	// BackgroundStarfield draws twinkling stars in parallax layers over
+	// the gradient.
+	BackgroundStarfield
+	// BackgroundImage draws an image file scaled to cover the screen. If
+	// the image cannot be loaded, the gradient is drawn instead.
+	BackgroundImage
+)
+
+// String returns the name of the background kind.
+func (k BackgroundKind) String() string {
+	switch k {
+	case BackgroundGradient:
+		return "Gradient"
+	case BackgroundStarfield:
+		return "Starfield"
+	case BackgroundImage:
+		return "Image"
+	default:
+		return "Solid"
+	}
+}
+
+// MaxPersistence is the highest persistence a Background can have. Frames
+// are faded in 8-bit color, so with weaker fading old frames would never
+// vanish completely but leave a visible residue.
+const MaxPersistence = 0.9
+
+// Background describes the scenery behind the particles of a preset.
+//
+// With Persistence above 0 the previous frame is not cleared but covered by
+// the background at an opacity of 1 - Persistence, so moving particles
+// leave long-exposure light trails.
+type Background struct {
+	Kind BackgroundKind
+	// Gradient colors; solid backgrounds use the top color
+	TopR, TopG, TopB          uint8
+	BottomR, BottomG, BottomB uint8
+	// Stars is the number of stars of a starfield, spread over StarLayers
+	// parallax layers; nearer layers move more with the camera.
+	Stars      int
+	StarLayers int
+	// ImagePath is the image file of an image background.
+	ImagePath string
+	// Persistence (0.0 to MaxPersistence) is how much of the previous
+	// frame remains visible.
+	Persistence float32
+}
+
+// DefaultBackground is the dark blue used by presets without a background
+// of their own.
+var DefaultBackground = SolidBackground(10, 10, 20)
+
+// SolidBackground creates a single color background.
+func SolidBackground(r, g, b uint8) Background {
+	return Background{Kind: BackgroundSolid, TopR: r, TopG: g, TopB: b, BottomR: r, BottomG: g, BottomB: b}
+}
+
+// GradientBackground creates a vertical gradient from the top to the
+// bottom color.
+func GradientBackground(tr, tg, tb, br, bg, bb uint8) Background {
+	return Background{Kind: BackgroundGradient, TopR: tr, TopG: tg, TopB: tb, BottomR: br, BottomG: bg, BottomB: bb}
+}
+
+// StarfieldBackground creates a starfield of stars spread over layers
+// parallax layers in front of a vertical gradient.
+func StarfieldBackground(stars, layers int, tr, tg, tb, br, bg, bb uint8) Background {
+	b := GradientBackground(tr, tg, tb, br, bg, bb)
+	b.Kind = BackgroundStarfield
+	b.Stars = stars
+	b.StarLayers = max(layers, 1)
+	return b
+}
+
+// ImageBackground creates a background showing the image file at path,
+// falling back to DefaultBackground's color.
+func ImageBackground(path string) Background {
+	b := DefaultBackground
+	b.Kind = BackgroundImage
+	b.ImagePath = path
+	return b
+}
+
+// WithPersistence returns a copy of the background that keeps the given
+// fraction of the previous frame, clamped to [0, MaxPersistence].
+func (b Background) WithPersistence(persistence float32) Background {
+	b.Persistence = min(max(persistence, 0), MaxPersistence)
+	return b
+}
+
+// FadeAlpha returns the opacity the background is drawn with each frame:
+// 255 clears the previous frame, lower values let it shine through.
+func (b Background) FadeAlpha() uint8 {
+	return uint8((1-b.Persistence)*255 + 0.5)
+}
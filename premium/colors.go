@@ -20,6 +20,8 @@
 //   - High: Maximum particles, full effects
 package premium
 
+import "github.com/deltatree/showcase/components"
+
 // ColorPalette defines a curated color scheme for a preset.
 // Each palette includes primary, accent, and glow colors.
 type ColorPalette struct {
@@ -33,6 +35,8 @@ type ColorPalette struct {
 	// Glow/bloom color overlay
 	GlowR, GlowG, GlowB uint8
 	GlowIntensity       float32
+	// Space is the color space particle gradients are interpolated in.
+	Space components.ColorSpace
 }
 
 // GalaxyPalette - Cosmic purple/blue with star-white accents
@@ -108,6 +112,8 @@ var ChaosPalette = ColorPalette{
 	// Glow: Hot white
 	GlowR: 255, GlowG: 220, GlowB: 255,
 	GlowIntensity: 0.9,
+	// OKLab keeps brightness even across the neon fades
+	Space: components.SpaceOKLab,
 }
 
 // Palettes maps preset names to their color palettes.
@@ -1,6 +1,9 @@
 package premium
 
-import "math/rand"
+import (
+	"math/rand"
+	"strings"
+)
 
 // ScreenEffects manages screen-wide visual effects.
 type ScreenEffects struct {
@@ -10,6 +13,9 @@ type ScreenEffects struct {
 	pulseScale     float32
 	pulseDuration  float32
 	pulseTimer     float32
+	flashAlpha     float32
+	flashDuration  float32
+	flashTimer     float32
 }
 
 // NewScreenEffects creates a new screen effects manager.
@@ -33,6 +39,14 @@ func (se *ScreenEffects) ApplyPulse(scale, duration float32) {
 	se.pulseTimer = duration
 }
 
+// ApplyFlash triggers a white screen flash starting at alpha (0.0 to 1.0)
+// and fading out over duration.
+func (se *ScreenEffects) ApplyFlash(alpha, duration float32) {
+	se.flashAlpha = min(max(alpha, 0), 1)
+	se.flashDuration = duration
+	se.flashTimer = duration
+}
+
 // Update advances effect timers.
 func (se *ScreenEffects) Update(dt float32) {
 	if se.shakeTimer > 0 {
@@ -47,6 +61,9 @@ func (se *ScreenEffects) Update(dt float32) {
 			se.pulseScale = 1.0
 		}
 	}
+	if se.flashTimer > 0 {
+		se.flashTimer -= dt
+	}
 }
 
 // GetShakeOffset returns the current shake offset.
@@ -70,9 +87,17 @@ func (se *ScreenEffects) GetPulseScale() float32 {
 	return 1.0 + (se.pulseScale-1.0)*progress
 }
 
+// GetFlashAlpha returns the current opacity of the screen flash.
+func (se *ScreenEffects) GetFlashAlpha() float32 {
+	if se.flashTimer <= 0 {
+		return 0
+	}
+	return se.flashAlpha * se.flashTimer / se.flashDuration
+}
+
 // IsActive returns true if any effect is active.
 func (se *ScreenEffects) IsActive() bool {
-	return se.shakeTimer > 0 || se.pulseTimer > 0
+	return se.shakeTimer > 0 || se.pulseTimer > 0 || se.flashTimer > 0
 }
 
 // Reset clears all effects.
@@ -81,6 +106,7 @@ func (se *ScreenEffects) Reset() {
 	se.shakeIntensity = 0
 	se.pulseTimer = 0
 	se.pulseScale = 1.0
+	se.flashTimer = 0
 }
 
 // JuiceLevel controls the intensity of visual feedback.
@@ -93,6 +119,41 @@ const (
 	JuiceIntense
 )
 
+// String returns the juice level name.
+func (l JuiceLevel) String() string {
+	switch l {
+	case JuiceOff:
+		return "Off"
+	case JuiceSubtle:
+		return "Subtle"
+	case JuiceNormal:
+		return "Normal"
+	case JuiceIntense:
+		return "Intense"
+	default:
+		return "Unknown"
+	}
+}
+
+// ParseJuiceLevel returns the juice level with the given name, case
+// insensitive. Unknown names give JuiceNormal.
+func ParseJuiceLevel(name string) JuiceLevel {
+	for level := JuiceOff; level <= JuiceIntense; level++ {
+		if strings.EqualFold(name, level.String()) {
+			return level
+		}
+	}
+	return JuiceNormal
+}
+
+// NextJuice cycles to the next juice level.
+func NextJuice(current JuiceLevel) JuiceLevel {
+	if current >= JuiceIntense || current < JuiceOff {
+		return JuiceOff
+	}
+	return current + 1
+}
+
 // JuiceConfig contains juice effect multipliers.
 type JuiceConfig struct {
 	Level           JuiceLevel
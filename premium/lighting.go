@@ -0,0 +1,40 @@
+package premium
+
+// Lighting describes how particles light up the scenery behind them.
+// Every particle is a light source with its color, as bright as it is
+// opaque; the background and the background layer are lit by the sum of
+// the lights on top of the ambient light, while particles and the layers
+// in front keep their own colors.
+type Lighting struct {
+	// Ambient light reaching the scenery without any particle nearby
+	AmbientR, AmbientG, AmbientB uint8
+	// Radius is the reach of a particle's light as a multiple of its
+	// radius.
+	Radius float32
+	// Intensity scales the brightness of every light; an opaque particle
+	// at intensity 1 lights the scenery at its center with its full color.
+	Intensity float32
+}
+
+// NewLighting creates lighting with a dim blue ambient light.
+func NewLighting() *Lighting {
+	return &Lighting{AmbientR: 40, AmbientG: 40, AmbientB: 60, Radius: 24, Intensity: 0.6}
+}
+
+// WithAmbient sets the ambient light.
+func (l *Lighting) WithAmbient(r, g, b uint8) *Lighting {
+	l.AmbientR, l.AmbientG, l.AmbientB = r, g, b
+	return l
+}
+
+// WithRadius sets the reach of a particle's light, at least its radius.
+func (l *Lighting) WithRadius(radius float32) *Lighting {
+	l.Radius = max(radius, 1)
+	return l
+}
+
+// WithIntensity sets the brightness of the lights.
+func (l *Lighting) WithIntensity(intensity float32) *Lighting {
+	l.Intensity = max(intensity, 0)
+	return l
+}
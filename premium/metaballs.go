@@ -0,0 +1,45 @@
+package premium
+
+// Metaballs describes the liquid look of particles drawn as metaballs:
+// each particle spreads a soft density around it, and wherever the summed
+// density of nearby particles exceeds the threshold they merge into one
+// blob.
+type Metaballs struct {
+	// Spread is the reach of a particle's density as a multiple of its
+	// radius. Larger values merge particles further apart.
+	Spread float32
+	// Threshold is the density at the blob surface (0.05 to
+	// MaxMetaballThreshold); a single particle has density 1 at its
+	// center.
+	Threshold float32
+	// Edge is the width of the soft blob outline in density units.
+	Edge float32
+}
+
+// MaxMetaballThreshold is the highest density a blob surface can have.
+// Densities are accumulated in 8-bit color and saturate above 2.
+const MaxMetaballThreshold = 1.5
+
+// NewMetaballs creates metaballs that merge particles within about three
+// radii of each other.
+func NewMetaballs() *Metaballs {
+	return &Metaballs{Spread: 3, Threshold: 0.5, Edge: 0.08}
+}
+
+// WithSpread sets the reach of a particle's density, at least its radius.
+func (m *Metaballs) WithSpread(spread float32) *Metaballs {
+	m.Spread = max(spread, 1)
+	return m
+}
+
+// WithThreshold sets the density at the blob surface.
+func (m *Metaballs) WithThreshold(threshold float32) *Metaballs {
+	m.Threshold = clamp(threshold, 0.05, MaxMetaballThreshold)
+	return m
+}
+
+// WithEdge sets the width of the blob outline.
+func (m *Metaballs) WithEdge(edge float32) *Metaballs {
+	m.Edge = max(edge, 0.001)
+	return m
+}
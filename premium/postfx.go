@@ -0,0 +1,85 @@
+package premium
+
+// PostEffectKind selects a full-screen post-processing effect.
+type PostEffectKind int
+
+const (
+	// EffectVignette darkens the screen towards the corners.
+	EffectVignette PostEffectKind = iota
+	// EffectChromaticAberration splits the color channels towards the
+	// screen edges, like a cheap lens.
+	EffectChromaticAberration
+	// EffectFilmGrain adds animated noise.
+	EffectFilmGrain
+	// EffectCRT bends the picture like a tube screen and darkens every
+	// other line.
+	EffectCRT
+
+	// PostEffectKinds is the number of effect kinds.
+	PostEffectKinds = iota
+)
+
+// String returns the name of the effect kind.
+func (k PostEffectKind) String() string {
+	switch k {
+	case EffectVignette:
+		return "Vignette"
+	case EffectChromaticAberration:
+		return "Chromatic Aberration"
+	case EffectFilmGrain:
+		return "Film Grain"
+	case EffectCRT:
+		return "CRT"
+	default:
+		return "Unknown"
+	}
+}
+
+// Limits of the effect parameters.
+const (
+	// MaxAberration is the largest channel offset in pixels.
+	MaxAberration = 20
+	// MaxCurvature is the strongest CRT screen curvature.
+	MaxCurvature = 0.5
+)
+
+// PostEffect is one effect of a post-process stack applied to the final
+// frame. Presets return an ordered list of effects; each effect processes
+// the output of the previous one.
+type PostEffect struct {
+	Kind PostEffectKind
+	// Strength is the main parameter: the vignette darkness (0.0 to 1.0),
+	// the channel offset at the screen edges in pixels, the grain amount
+	// (0.0 to 1.0) or the CRT scanline darkness (0.0 to 1.0).
+	Strength float32
+	// Shape is the vignette radius (0.0 to 1.0) where darkening starts
+	// or the CRT curvature (0.0 to MaxCurvature).
+	Shape float32
+}
+
+// Vignette creates a vignette darkening the corners by strength, starting
+// at radius (a fraction of the distance to the corners).
+func Vignette(strength, radius float32) PostEffect {
+	return PostEffect{Kind: EffectVignette, Strength: clamp(strength, 0, 1), Shape: clamp(radius, 0, 1)}
+}
+
+// ChromaticAberration creates a color fringe offsetting the red and blue
+// channels by up to offset pixels at the screen edges.
+func ChromaticAberration(offset float32) PostEffect {
+	return PostEffect{Kind: EffectChromaticAberration, Strength: clamp(offset, 0, MaxAberration)}
+}
+
+// FilmGrain creates noise of the given amount.
+func FilmGrain(amount float32) PostEffect {
+	return PostEffect{Kind: EffectFilmGrain, Strength: clamp(amount, 0, 1)}
+}
+
+// CRT creates a tube screen look with scanlines of the given darkness and
+// a curved picture.
+func CRT(scanlines, curvature float32) PostEffect {
+	return PostEffect{Kind: EffectCRT, Strength: clamp(scanlines, 0, 1), Shape: clamp(curvature, 0, MaxCurvature)}
+}
+
+func clamp(v, lo, hi float32) float32 {
+	return min(max(v, lo), hi)
+}
@@ -2,6 +2,8 @@ package premium
 
 import (
 	"testing"
+
+	"github.com/deltatree/showcase/components"
 )
 
 func TestGetPalette(t *testing.T) {
@@ -39,6 +41,15 @@ func TestColorPaletteValues(t *testing.T) {
 	}
 }
 
+func TestChaosPaletteOKLab(t *testing.T) {
+	if ChaosPalette.Space != components.SpaceOKLab {
+		t.Errorf("ChaosPalette.Space = %v, want OKLab", ChaosPalette.Space)
+	}
+	if GalaxyPalette.Space != components.SpaceSRGB {
+		t.Errorf("GalaxyPalette.Space = %v, want sRGB", GalaxyPalette.Space)
+	}
+}
+
 func TestQualityLevelString(t *testing.T) {
 	tests := []struct {
 		level    QualityLevel
@@ -83,6 +94,33 @@ func TestGetQualitySettings(t *testing.T) {
 	}
 }
 
+func TestGetQualitySettings_Bloom(t *testing.T) {
+	low := GetQualitySettings(QualityLow)
+	if low.Bloom {
+		t.Error("Low quality should disable bloom")
+	}
+	med := GetQualitySettings(QualityMedium)
+	high := GetQualitySettings(QualityHigh)
+	if !med.Bloom || !high.Bloom {
+		t.Error("Medium and High quality should enable bloom")
+	}
+	if high.BloomDownsample >= med.BloomDownsample {
+		t.Errorf("High BloomDownsample = %d, want finer than Medium (%d)", high.BloomDownsample, med.BloomDownsample)
+	}
+	if high.BloomRadius <= med.BloomRadius {
+		t.Errorf("High BloomRadius = %v, want wider than Medium (%v)", high.BloomRadius, med.BloomRadius)
+	}
+}
+
+func TestGetQualitySettings_PostFX(t *testing.T) {
+	if GetQualitySettings(QualityLow).PostFX {
+		t.Error("Low quality should disable post-processing")
+	}
+	if !GetQualitySettings(QualityMedium).PostFX || !GetQualitySettings(QualityHigh).PostFX {
+		t.Error("Medium and High quality should enable post-processing")
+	}
+}
+
 func TestNextQuality(t *testing.T) {
 	tests := []struct {
 		current  QualityLevel
@@ -163,6 +201,52 @@ func TestJuiceConfig(t *testing.T) {
 	}
 }
 
+func TestScreenEffectsFlash(t *testing.T) {
+	se := NewScreenEffects()
+	se.ApplyFlash(2, 0.4)
+	if a := se.GetFlashAlpha(); a != 1 {
+		t.Errorf("GetFlashAlpha() = %f, want clamped to 1", a)
+	}
+	if !se.IsActive() {
+		t.Error("Flash should make effects active")
+	}
+
+	se.Update(0.2)
+	if a := se.GetFlashAlpha(); a < 0.49 || a > 0.51 {
+		t.Errorf("GetFlashAlpha() = %f, want 0.5 halfway", a)
+	}
+
+	se.Reset()
+	if se.GetFlashAlpha() != 0 || se.IsActive() {
+		t.Error("Reset should clear the flash")
+	}
+}
+
+func TestJuiceLevelNames(t *testing.T) {
+	for level := JuiceOff; level <= JuiceIntense; level++ {
+		if got := ParseJuiceLevel(level.String()); got != level {
+			t.Errorf("ParseJuiceLevel(%q) = %v, want %v", level.String(), got, level)
+		}
+	}
+	if got := ParseJuiceLevel("INTENSE"); got != JuiceIntense {
+		t.Errorf("ParseJuiceLevel should ignore case, got %v", got)
+	}
+	if got := ParseJuiceLevel("bogus"); got != JuiceNormal {
+		t.Errorf("ParseJuiceLevel(bogus) = %v, want Normal", got)
+	}
+	if JuiceLevel(9).String() != "Unknown" {
+		t.Errorf("JuiceLevel(9).String() = %q, want Unknown", JuiceLevel(9).String())
+	}
+
+	level := JuiceOff
+	for i := 0; i < 4; i++ {
+		level = NextJuice(level)
+	}
+	if level != JuiceOff {
+		t.Errorf("NextJuice should cycle through 4 levels, got %v", level)
+	}
+}
+
 func TestAudioManager(t *testing.T) {
 	am := NewAudioManager()
 
@@ -350,6 +434,15 @@ func TestUILayout_BottomRight(t *testing.T) {
 	}
 }
 
+// TestUILayout_Resize tests that positions follow the new dimensions.
+func TestUILayout_Resize(t *testing.T) {
+	l := NewUILayout(1280, 720)
+	l.Resize(1920, 1080)
+	if x, y := l.BottomRight(); x != 1920-l.MarginX || y != 1080-l.MarginY {
+		t.Errorf("BottomRight after Resize = (%d, %d), want (%d, %d)", x, y, 1920-l.MarginX, 1080-l.MarginY)
+	}
+}
+
 // TestUILayout_Center tests Center method.
 func TestUILayout_Center(t *testing.T) {
 	l := NewUILayout(1280, 720)
@@ -422,3 +515,103 @@ func TestQualitySettings_UltraLevel(t *testing.T) {
 		t.Errorf("Unknown quality level should return Medium settings, got MaxParticles=%d", q.MaxParticles)
 	}
 }
+
+// TestBackgrounds tests the background constructors.
+func TestBackgrounds(t *testing.T) {
+	if b := DefaultBackground; b.Kind != BackgroundSolid || b.TopR != 10 || b.BottomB != 20 || b.FadeAlpha() != 255 {
+		t.Errorf("DefaultBackground = %+v, want solid (10, 10, 20) that clears", b)
+	}
+
+	g := GradientBackground(1, 2, 3, 4, 5, 6)
+	if g.Kind != BackgroundGradient || g.TopB != 3 || g.BottomR != 4 {
+		t.Errorf("GradientBackground = %+v", g)
+	}
+
+	s := StarfieldBackground(300, 0, 0, 0, 0, 0, 0, 0)
+	if s.Kind != BackgroundStarfield || s.Stars != 300 || s.StarLayers != 1 {
+		t.Errorf("StarfieldBackground = %+v, want 300 stars in at least one layer", s)
+	}
+
+	if i := ImageBackground("sky.png"); i.Kind != BackgroundImage || i.ImagePath != "sky.png" || i.TopR != 10 {
+		t.Errorf("ImageBackground = %+v", i)
+	}
+
+	if BackgroundStarfield.String() != "Starfield" || BackgroundKind(42).String() != "Solid" {
+		t.Error("BackgroundKind.String() mismatch")
+	}
+}
+
+// TestBackground_Persistence tests the clamping and fade alpha.
+func TestBackground_Persistence(t *testing.T) {
+	tests := []struct {
+		persistence float32
+		want        float32
+		alpha       uint8
+	}{
+		{0, 0, 255},
+		{0.8, 0.8, 51},
+		{1.5, MaxPersistence, 26},
+		{-1, 0, 255},
+	}
+	for _, tt := range tests {
+		b := DefaultBackground.WithPersistence(tt.persistence)
+		if b.Persistence != tt.want || b.FadeAlpha() != tt.alpha {
+			t.Errorf("WithPersistence(%v) = %v (alpha %d), want %v (alpha %d)", tt.persistence, b.Persistence, b.FadeAlpha(), tt.want, tt.alpha)
+		}
+	}
+	if DefaultBackground.Persistence != 0 {
+		t.Error("WithPersistence should not modify the receiver")
+	}
+}
+
+// TestPostEffects tests the effect constructors and their clamping.
+func TestPostEffects(t *testing.T) {
+	tests := []struct {
+		effect          PostEffect
+		kind            PostEffectKind
+		strength, shape float32
+	}{
+		{Vignette(0.5, 0.4), EffectVignette, 0.5, 0.4},
+		{Vignette(2, -1), EffectVignette, 1, 0},
+		{ChromaticAberration(3), EffectChromaticAberration, 3, 0},
+		{ChromaticAberration(100), EffectChromaticAberration, MaxAberration, 0},
+		{FilmGrain(-0.5), EffectFilmGrain, 0, 0},
+		{CRT(0.3, 1), EffectCRT, 0.3, MaxCurvature},
+	}
+	for _, tt := range tests {
+		e := tt.effect
+		if e.Kind != tt.kind || e.Strength != tt.strength || e.Shape != tt.shape {
+			t.Errorf("%s = %+v, want strength %v, shape %v", tt.kind, e, tt.strength, tt.shape)
+		}
+	}
+	if EffectChromaticAberration.String() != "Chromatic Aberration" || PostEffectKind(42).String() != "Unknown" {
+		t.Error("PostEffectKind.String() mismatch")
+	}
+}
+
+// TestMetaballs tests the metaball defaults and their clamping.
+func TestMetaballs(t *testing.T) {
+	m := NewMetaballs()
+	if m.Spread != 3 || m.Threshold != 0.5 || m.Edge <= 0 {
+		t.Errorf("NewMetaballs() = %+v", m)
+	}
+	m.WithSpread(0.5).WithThreshold(5).WithEdge(-1)
+	if m.Spread != 1 || m.Threshold != MaxMetaballThreshold || m.Edge <= 0 {
+		t.Errorf("clamped metaballs = %+v, want spread 1, threshold %v and a positive edge", m, MaxMetaballThreshold)
+	}
+	if m.WithThreshold(0).Threshold <= 0 {
+		t.Error("a zero threshold would fill the whole screen")
+	}
+}
+
+// TestLighting tests the lighting defaults and their clamping.
+func TestLighting(t *testing.T) {
+	l := NewLighting()
+	if l.Radius <= 1 || l.Intensity <= 0 || l.AmbientB == 0 {
+		t.Errorf("NewLighting() = %+v", l)
+	}
+	l.WithAmbient(1, 2, 3).WithRadius(0).WithIntensity(-1)
+	if l.AmbientR != 1 || l.AmbientB != 3 || l.Radius != 1 || l.Intensity != 0 {
+		t.Errorf("clamped lighting = %+v, want ambient (1, 2, 3), radius 1 and intensity 0", l)
+	}
+}
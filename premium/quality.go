@@ -33,6 +33,14 @@ type QualitySettings struct {
 	BlurSamples  int
 	ParticleSize float32
 	TrailLength  int
+	// Bloom enables the GPU bloom post-process.
+	Bloom bool
+	// BloomRadius is the blur radius in texels of the downsampled buffer.
+	BloomRadius float32
+	// BloomDownsample divides the screen size for the blur buffers.
+	BloomDownsample int
+	// PostFX applies the preset's post-process effect stack.
+	PostFX bool
 }
 
 // Low quality preset - for older hardware
@@ -45,6 +53,11 @@ var qualityLow = QualitySettings{
 	BlurSamples:  0,
 	ParticleSize: 3.0,
 	TrailLength:  0,
+
+	Bloom:           false,
+	BloomRadius:     0,
+	BloomDownsample: 8,
+	PostFX:          false,
 }
 
 // Medium quality preset - balanced
@@ -57,6 +70,11 @@ var qualityMedium = QualitySettings{
 	BlurSamples:  0,
 	ParticleSize: 2.5,
 	TrailLength:  3,
+
+	Bloom:           true,
+	BloomRadius:     1.5,
+	BloomDownsample: 4,
+	PostFX:          true,
 }
 
 // High quality preset - full visual experience
@@ -69,6 +87,11 @@ var qualityHigh = QualitySettings{
 	BlurSamples:  4,
 	ParticleSize: 2.0,
 	TrailLength:  5,
+
+	Bloom:           true,
+	BloomRadius:     2.5,
+	BloomDownsample: 2,
+	PostFX:          true,
 }
 
 // GetQualitySettings returns settings for a quality level.
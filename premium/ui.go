@@ -94,6 +94,12 @@ func NewUILayout(width, height int) *UILayout {
 	}
 }
 
+// Resize sets the layout dimensions, e.g. after the window was resized.
+func (l *UILayout) Resize(width, height int) {
+	l.Width = width
+	l.Height = height
+}
+
 // TopLeft returns top-left position.
 func (l *UILayout) TopLeft() (x, y int) {
 	return l.MarginX, l.MarginY
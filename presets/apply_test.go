@@ -4,7 +4,12 @@ import (
 	"testing"
 
 	"github.com/andygeiss/ecs"
+	"github.com/deltatree/showcase/components"
+	"github.com/deltatree/showcase/curves"
 	"github.com/deltatree/showcase/internal/config"
+	"github.com/deltatree/showcase/premium"
+	"github.com/deltatree/showcase/shapes"
+	"github.com/deltatree/showcase/systems"
 )
 
 // TestPresetApply tests the Apply method for all presets.
@@ -124,6 +129,23 @@ func TestClearParticles(t *testing.T) {
 	// We can't easily count entities, but the function should not panic
 }
 
+// TestPresetParticleIDs tests that preset particles have distinct Ids, so
+// removing one by Id never removes another.
+func TestPresetParticleIDs(t *testing.T) {
+	cfg := config.Default()
+	for _, preset := range Registry {
+		em := ecs.NewEntityManager()
+		preset.Apply(em, cfg)
+		seen := make(map[string]bool)
+		for _, e := range em.FilterByMask(components.MaskParticle) {
+			if seen[e.Id] {
+				t.Fatalf("%s: Id %q is shared", preset.Name(), e.Id)
+			}
+			seen[e.Id] = true
+		}
+	}
+}
+
 // TestEmitterConfig tests that presets provide emitter configuration.
 func TestEmitterConfig(t *testing.T) {
 	presets := []struct {
@@ -158,3 +180,370 @@ func TestEmitterConfig(t *testing.T) {
 		})
 	}
 }
+
+// TestMaskAttractors tests adding and removing per-point attractors.
+func TestMaskAttractors(t *testing.T) {
+	em := ecs.NewEntityManager()
+	em.Add(ecs.NewEntity("mouse-attractor", []ecs.Component{
+		components.NewPosition(),
+		components.NewMass(),
+		components.NewAttractor(),
+	}))
+
+	points := shapes.PointSet{{X: 10, Y: 20}, {X: 30, Y: 40}, {X: 50, Y: 60}}
+	added := AddMaskAttractors(em, points, 100)
+
+	if len(added) != 3 {
+		t.Fatalf("AddMaskAttractors() returned %d entities, want 3", len(added))
+	}
+	pos := added[1].Get(components.MaskPosition).(*components.Position)
+	if pos.X != 30 || pos.Y != 40 {
+		t.Errorf("attractor 1 at (%v, %v), want (30, 40)", pos.X, pos.Y)
+	}
+	if got := len(em.FilterByMask(components.MaskAttractor)); got != 4 {
+		t.Errorf("expected 4 attractors, got %d", got)
+	}
+
+	RemoveMaskAttractors(em)
+
+	remaining := em.FilterByMask(components.MaskAttractor)
+	if len(remaining) != 1 || remaining[0].Id != "mouse-attractor" {
+		t.Errorf("RemoveMaskAttractors() should keep only the mouse attractor, got %d", len(remaining))
+	}
+}
+
+// TestAssignTargets tests that particles receive targets from a point set.
+func TestAssignTargets(t *testing.T) {
+	cfg := config.Default()
+	em := ecs.NewEntityManager()
+	NewChaosPreset().Apply(em, cfg)
+
+	points := shapes.Circle(640, 360, 100, 50)
+	if got := AssignTargets(em, points, 6); got != 50 {
+		t.Fatalf("AssignTargets() = %d, want 50", got)
+	}
+
+	held := em.FilterByMask(components.MaskTarget)
+	if len(held) != 50 {
+		t.Fatalf("expected 50 particles with targets, got %d", len(held))
+	}
+	target := held[0].Get(components.MaskTarget).(*components.Target)
+	if !target.Active || target.Strength != 6 {
+		t.Errorf("target = %+v, want active with strength 6", target)
+	}
+
+	// Re-assigning reuses the existing Target components.
+	AssignTargets(em, shapes.Circle(0, 0, 10, 2000), 3)
+	if got := len(em.FilterByMask(components.MaskTarget)); got != len(em.FilterByMask(components.MaskParticle)) {
+		t.Errorf("expected all particles to hold targets, got %d", got)
+	}
+}
+
+// TestReleaseTargets tests that releasing removes targets and adds burst velocity.
+func TestReleaseTargets(t *testing.T) {
+	em := ecs.NewEntityManager()
+	vel := components.NewVelocity()
+	em.Add(ecs.NewEntity("a", []ecs.Component{
+		components.NewPosition().With(0, 0),
+		vel,
+		components.NewTarget(),
+	}))
+	em.Add(ecs.NewEntity("b", []ecs.Component{
+		components.NewPosition().With(100, 0),
+		components.NewVelocity(),
+		components.NewTarget(),
+	}))
+
+	ReleaseTargets(em, 200)
+
+	if got := len(em.FilterByMask(components.MaskTarget)); got != 0 {
+		t.Errorf("expected no targets after release, got %d", got)
+	}
+	if vel.X >= 0 {
+		t.Errorf("particle left of center should fly left, got vx=%v", vel.X)
+	}
+}
+
+// TestMorph_QueryCache tests that assigning and releasing targets keeps
+// cached queries up to date.
+func TestMorph_QueryCache(t *testing.T) {
+	em := systems.NewQueryCache()
+	NewChaosPreset().Apply(em, config.Default())
+
+	if em.Count(components.MaskTarget) != 0 {
+		t.Fatal("expected no targets before morphing")
+	}
+	AssignTargets(em, shapes.Circle(640, 360, 100, 50), 6)
+	if got := em.Count(components.MaskTarget); got != 50 {
+		t.Errorf("Count(Target) = %d, want 50", got)
+	}
+	ReleaseTargets(em, 200)
+	if got := em.Count(components.MaskTarget); got != 0 {
+		t.Errorf("Count(Target) = %d, want 0 after release", got)
+	}
+}
+
+// TestFireGradient tests the fire color ramp from white to transparent smoke.
+func TestFireGradient(t *testing.T) {
+	g := FireGradient()
+
+	if r, gr, b, a := g.Evaluate(0); r != 255 || gr != 255 || b != 255 || a != 255 {
+		t.Errorf("fire should start white, got (%d, %d, %d, %d)", r, gr, b, a)
+	}
+	if r, gr, _, _ := g.Evaluate(0.5); r <= gr {
+		t.Errorf("fire should be red at half life, got r=%d g=%d", r, gr)
+	}
+	if _, _, _, a := g.Evaluate(1); a != 0 {
+		t.Errorf("fire should end transparent, got alpha %d", a)
+	}
+
+	type gradientConfig interface {
+		GradientConfig() *components.Gradient
+	}
+	if _, ok := NewFireworkPreset().(gradientConfig); !ok {
+		t.Error("Firework preset should provide a gradient")
+	}
+}
+
+// TestFireworkOverLifeConfig tests the Firework ember curves.
+func TestFireworkOverLifeConfig(t *testing.T) {
+	type overLifeConfig interface {
+		OverLifeConfig() (size, alpha, speed curves.Curve)
+	}
+	p, ok := NewFireworkPreset().(overLifeConfig)
+	if !ok {
+		t.Fatal("Firework preset should provide over-life curves")
+	}
+	size, alpha, speed := p.OverLifeConfig()
+	if size.At(0.1) <= size.At(1) {
+		t.Error("embers should shrink towards the end of their life")
+	}
+	if alpha.At(0) != 1 || alpha.At(1) != 0 {
+		t.Errorf("alpha should fade from 1 to 0, got %v to %v", alpha.At(0), alpha.At(1))
+	}
+	if speed.At(1) >= speed.At(0) {
+		t.Error("embers should slow down")
+	}
+}
+
+// TestPresetBlendModes tests the blend mode each preset asks for.
+func TestPresetBlendModes(t *testing.T) {
+	type blendPreset interface {
+		BlendMode() components.BlendMode
+	}
+	tests := []struct {
+		preset Preset
+		want   components.BlendMode
+	}{
+		{NewGalaxyPreset(), components.BlendAdditive},
+		{NewFireworkPreset(), components.BlendAdditive},
+		{NewChaosPreset(), components.BlendScreen},
+		{NewSwarmPreset(), components.BlendAlpha},
+		{NewFountainPreset(), components.BlendAlpha},
+	}
+	for _, tt := range tests {
+		got := components.BlendAlpha
+		if p, ok := tt.preset.(blendPreset); ok {
+			got = p.BlendMode()
+		}
+		if got != tt.want {
+			t.Errorf("%s blend mode = %s, want %s", tt.preset.Name(), got, tt.want)
+		}
+	}
+}
+
+// TestSpriteAtlases tests the built-in sprite sheets.
+func TestSpriteAtlases(t *testing.T) {
+	frames := map[string]int{SheetSpark: 4, SheetSmoke: 4, SheetSnowflake: 1, SheetLeaf: 1, SheetSkyline: 1}
+	for _, a := range SpriteAtlases() {
+		want, ok := frames[a.Name]
+		if !ok {
+			t.Errorf("unexpected sheet %q", a.Name)
+			continue
+		}
+		delete(frames, a.Name)
+		b := a.Image.Bounds()
+		if b.Dx() != want*a.FrameWidth || b.Dy() != a.FrameHeight {
+			t.Errorf("%s: image %dx%d, want %d frames of %dx%d", a.Name, b.Dx(), b.Dy(), want, a.FrameWidth, a.FrameHeight)
+		}
+		// The frame center is drawn, the corners are transparent; the
+		// skyline stands on the bottom edge
+		cx, cy := a.FrameWidth/2, a.FrameHeight/2
+		if a.Name == SheetSkyline {
+			cy = a.FrameHeight - 1
+		}
+		if _, _, _, alpha := a.Image.At(cx, cy).RGBA(); alpha == 0 {
+			t.Errorf("%s: frame center is transparent", a.Name)
+		}
+		if _, _, _, alpha := a.Image.At(0, 0).RGBA(); alpha != 0 {
+			t.Errorf("%s: frame corner is not transparent", a.Name)
+		}
+	}
+	for name := range frames {
+		t.Errorf("missing sheet %q", name)
+	}
+
+	type spritePreset interface {
+		SpriteConfig() (*components.Sprite, float32)
+	}
+	p, ok := NewFireworkPreset().(spritePreset)
+	if !ok {
+		t.Fatal("Firework preset should provide a sprite")
+	}
+	if sprite, _ := p.SpriteConfig(); sprite.Sheet != SheetSpark {
+		t.Errorf("Firework sprite sheet = %q, want %q", sprite.Sheet, SheetSpark)
+	}
+}
+
+// TestPresetBackgrounds tests the background each preset asks for.
+func TestPresetBackgrounds(t *testing.T) {
+	type backgroundPreset interface {
+		Background() premium.Background
+	}
+	tests := []struct {
+		preset     Preset
+		kind       premium.BackgroundKind
+		persistent bool
+	}{
+		{NewGalaxyPreset(), premium.BackgroundStarfield, false},
+		{NewFireworkPreset(), premium.BackgroundGradient, true},
+		{NewChaosPreset(), premium.BackgroundSolid, true},
+		{NewSwarmPreset(), premium.BackgroundSolid, false},
+		{NewFountainPreset(), premium.BackgroundGradient, false},
+	}
+	for _, tt := range tests {
+		got := premium.DefaultBackground
+		if p, ok := tt.preset.(backgroundPreset); ok {
+			got = p.Background()
+		}
+		if got.Kind != tt.kind {
+			t.Errorf("%s background = %s, want %s", tt.preset.Name(), got.Kind, tt.kind)
+		}
+		if persistent := got.Persistence > 0; persistent != tt.persistent {
+			t.Errorf("%s persistent = %v, want %v", tt.preset.Name(), persistent, tt.persistent)
+		}
+	}
+}
+
+// TestPresetPostEffects tests that every preset asks for a valid effect
+// stack.
+func TestPresetPostEffects(t *testing.T) {
+	type postEffectsPreset interface {
+		PostEffects() []premium.PostEffect
+	}
+	for _, preset := range Registry {
+		p, ok := preset.(postEffectsPreset)
+		if !ok {
+			continue
+		}
+		effects := p.PostEffects()
+		if len(effects) == 0 {
+			t.Errorf("%s has an empty effect stack", preset.Name())
+		}
+		for _, e := range effects {
+			if e.Kind < 0 || e.Kind >= premium.PostEffectKinds {
+				t.Errorf("%s: unknown effect %d", preset.Name(), e.Kind)
+			}
+		}
+	}
+}
+
+// TestPresetMetaballs tests that the fountain's water is drawn as
+// metaballs.
+func TestPresetMetaballs(t *testing.T) {
+	type metaballPreset interface {
+		MetaballConfig() *premium.Metaballs
+	}
+	p, ok := NewFountainPreset().(metaballPreset)
+	if !ok {
+		t.Fatal("Fountain preset should provide metaballs")
+	}
+	if m := p.MetaballConfig(); m == nil || m.Spread <= 1 {
+		t.Errorf("Fountain metaballs = %+v, want a spread beyond the particle radius", m)
+	}
+	if _, ok := NewGalaxyPreset().(metaballPreset); ok {
+		t.Error("Galaxy stars should stay circles")
+	}
+}
+
+// TestFireworkSkyline tests that Firework lights a skyline that other
+// presets remove again.
+func TestFireworkSkyline(t *testing.T) {
+	cfg := config.Default()
+	em := ecs.NewEntityManager()
+
+	firework := NewFireworkPreset()
+	if l, ok := firework.(interface{ LightingConfig() *premium.Lighting }); !ok || l.LightingConfig() == nil {
+		t.Fatal("Firework preset should light its scenery")
+	}
+	firework.Apply(em, cfg)
+	firework.Apply(em, cfg)
+
+	var skylines []*ecs.Entity
+	for _, e := range em.FilterByMask(components.MaskSprite) {
+		if e.Get(components.MaskSprite).(*components.Sprite).Sheet == SheetSkyline {
+			skylines = append(skylines, e)
+		}
+	}
+	if len(skylines) != 1 {
+		t.Fatalf("skylines = %d, want 1 after applying Firework twice", len(skylines))
+	}
+	e := skylines[0]
+	if e.Masked&components.MaskParticle != 0 {
+		t.Error("the skyline should not count as a particle")
+	}
+	if l := e.Get(components.MaskLayer).(*components.Layer); l.Layer != components.LayerBackground {
+		t.Errorf("skyline layer = %s, want Background", l.Layer)
+	}
+	pos := e.Get(components.MaskPosition).(*components.Position)
+	size := e.Get(components.MaskSize).(*components.Size)
+	bottom := pos.Y + size.Radius*skylineHeight/skylineWidth
+	if size.Radius != float32(cfg.Window.Width)/2 || bottom != float32(cfg.Window.Height) {
+		t.Errorf("skyline radius %v, bottom %v, want the window width and bottom", size.Radius, bottom)
+	}
+
+	NewGalaxyPreset().Apply(em, cfg)
+	if n := len(em.FilterByMask(components.MaskSprite)); n != 0 {
+		t.Errorf("%d sprites left after switching to Galaxy, want the skyline removed", n)
+	}
+}
+
+// TestPresetResize tests that presets move their layout into a resized
+// window instead of spawning it anew.
+func TestPresetResize(t *testing.T) {
+	type resizer interface {
+		Resize(em ecs.EntityManager, fromW, fromH, toW, toH float32)
+	}
+	cfg := config.Default()
+	w, h := float32(cfg.Window.Width), float32(cfg.Window.Height)
+
+	for _, preset := range Registry {
+		if _, ok := preset.(resizer); !ok {
+			t.Errorf("%s preset should handle resizes", preset.Name())
+		}
+	}
+
+	em := ecs.NewEntityManager()
+	galaxy := NewGalaxyPreset()
+	galaxy.Apply(em, cfg)
+	particles := em.FilterByMask(components.MaskParticle)
+	first := *particles[0].Get(components.MaskPosition).(*components.Position)
+	galaxy.(resizer).Resize(em, w, h, w+200, h+100)
+	if n := len(em.FilterByMask(components.MaskParticle)); n != len(particles) {
+		t.Errorf("particles = %d after resize, want the %d laid out", n, len(particles))
+	}
+	if pos := particles[0].Get(components.MaskPosition).(*components.Position); pos.X != first.X+100 || pos.Y != first.Y+50 {
+		t.Errorf("particle moved to (%v, %v), want (%v, %v)", pos.X, pos.Y, first.X+100, first.Y+50)
+	}
+
+	em = ecs.NewEntityManager()
+	firework := NewFireworkPreset()
+	firework.Apply(em, cfg)
+	firework.(resizer).Resize(em, w, h, 1920, 1080)
+	e := em.Get(skylineID)
+	pos := e.Get(components.MaskPosition).(*components.Position)
+	size := e.Get(components.MaskSize).(*components.Size)
+	if bottom := pos.Y + size.Radius*skylineHeight/skylineWidth; size.Radius != 960 || bottom != 1080 {
+		t.Errorf("skyline radius %v, bottom %v, want 960 and 1080", size.Radius, bottom)
+	}
+}
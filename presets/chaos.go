@@ -37,6 +37,33 @@ func (p *chaosPreset) Palette() premium.ColorPalette {
 	return p.palette
 }
 
+// BlendMode returns the blend mode for this preset's particles, so
+// colliding colors brighten without clipping.
+func (p *chaosPreset) BlendMode() components.BlendMode {
+	return components.BlendScreen
+}
+
+// Background returns the default background with short trails that smear
+// the turbulence.
+func (p *chaosPreset) Background() premium.Background {
+	return premium.DefaultBackground.WithPersistence(0.6)
+}
+
+// PostEffects returns a glitchy tube screen look.
+func (p *chaosPreset) PostEffects() []premium.PostEffect {
+	return []premium.PostEffect{
+		premium.ChromaticAberration(4),
+		premium.CRT(0.3, 0.1),
+		premium.FilmGrain(0.08),
+	}
+}
+
+// Resize spreads the particles over a window resized from fromW × fromH
+// to toW × toH.
+func (p *chaosPreset) Resize(em ecs.EntityManager, fromW, fromH, toW, toH float32) {
+	scaleParticles(em, toW/fromW, toH/fromH)
+}
+
 func (p *chaosPreset) Apply(em ecs.EntityManager, cfg *config.Config) {
 	ClearParticles(em)
 
@@ -72,11 +99,11 @@ func (p *chaosPreset) Apply(em ecs.EntityManager, cfg *config.Config) {
 			er, eg, eb, ea = sr, sg, sb, 0
 		}
 
-		em.Add(ecs.NewEntity("", []ecs.Component{
+		em.Add(ecs.NewEntity(components.NewParticleID(), []ecs.Component{
 			components.NewPosition().With(x, y),
 			components.NewVelocity().With(vx, vy),
 			components.NewAcceleration(),
-			components.NewColor().WithGradient(sr, sg, sb, sa, er, eg, eb, ea),
+			components.NewColor().WithGradient(sr, sg, sb, sa, er, eg, eb, ea).WithSpace(pal.Space),
 			components.NewLifetime().WithTTL(3.0 + rand.Float32()*4.0),
 			components.NewSize().WithRadius(1.0 + rand.Float32()*4.0).WithEndSize(0.5),
 			components.NewParticle(),
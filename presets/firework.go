@@ -6,6 +6,7 @@ import (
 
 	"github.com/andygeiss/ecs"
 	"github.com/deltatree/showcase/components"
+	"github.com/deltatree/showcase/curves"
 	"github.com/deltatree/showcase/internal/config"
 	"github.com/deltatree/showcase/premium"
 )
@@ -37,6 +38,41 @@ func (p *fireworkPreset) Palette() premium.ColorPalette {
 	return p.palette
 }
 
+// BlendMode returns the blend mode for this preset's particles, so
+// overlapping sparks burn towards white.
+func (p *fireworkPreset) BlendMode() components.BlendMode {
+	return components.BlendAdditive
+}
+
+// Background returns a night sky that keeps fading light trails of the
+// bursts, like a long exposure.
+func (p *fireworkPreset) Background() premium.Background {
+	return premium.GradientBackground(5, 5, 25, 30, 15, 40).WithPersistence(0.8)
+}
+
+// PostEffects returns the grain and dark corners of a night photograph.
+func (p *fireworkPreset) PostEffects() []premium.PostEffect {
+	return []premium.PostEffect{
+		premium.Vignette(0.6, 0.35),
+		premium.FilmGrain(0.05),
+	}
+}
+
+// LightingConfig returns the lighting of the skyline: dark until the
+// sparks light it up in their colors.
+func (p *fireworkPreset) LightingConfig() *premium.Lighting {
+	return premium.NewLighting().WithAmbient(25, 25, 45).WithRadius(30).WithIntensity(0.8)
+}
+
+// Resize moves the skyline to the bottom of a window resized from fromW ×
+// fromH to toW × toH and keeps the explosions centered.
+func (p *fireworkPreset) Resize(em ecs.EntityManager, fromW, fromH, toW, toH float32) {
+	if e := em.Get(skylineID); e != nil {
+		layoutSkyline(e, toW, toH)
+	}
+	moveParticles(em, (toW-fromW)/2, (toH-fromH)/2)
+}
+
 func (p *fireworkPreset) Apply(em ecs.EntityManager, cfg *config.Config) {
 	ClearParticles(em)
 
@@ -44,6 +80,9 @@ func (p *fireworkPreset) Apply(em ecs.EntityManager, cfg *config.Config) {
 	height := float32(cfg.Window.Height)
 	pal := p.palette
 
+	// The bursts light up a city skyline
+	AddSkyline(em, width, height, 90, 100, 140)
+
 	numExplosions := 5
 	for e := 0; e < numExplosions; e++ {
 		explosionX := rand.Float32() * width
@@ -66,11 +105,11 @@ func (p *fireworkPreset) Apply(em ecs.EntityManager, cfg *config.Config) {
 			vx := float32(math.Cos(float64(angle))) * speed
 			vy := float32(math.Sin(float64(angle)))*speed - 50
 
-			em.Add(ecs.NewEntity("", []ecs.Component{
+			em.Add(ecs.NewEntity(components.NewParticleID(), []ecs.Component{
 				components.NewPosition().With(explosionX, explosionY),
 				components.NewVelocity().With(vx, vy),
 				components.NewAcceleration().WithY(100),
-				components.NewColor().WithGradient(c.r, c.g, c.b, 255, c.r, c.g, c.b, 0),
+				components.NewColor().WithGradient(c.r, c.g, c.b, 255, c.r, c.g, c.b, 0).WithSpace(pal.Space),
 				components.NewLifetime().WithTTL(1.5 + rand.Float32()*1.5),
 				components.NewSize().WithRadius(2.0 + rand.Float32()*3.0).WithEndSize(0.5),
 				components.NewParticle(),
@@ -85,3 +124,22 @@ func (p *fireworkPreset) EmitterConfig() (sr, sg, sb, sa, er, eg, eb, ea uint8,
 	return pal.StartR, pal.StartG, pal.StartB, pal.StartA,
 		pal.EndR, pal.EndG, pal.EndB, pal.EndA, "edges", 20
 }
+
+// OverLifeConfig returns the size, alpha and speed curves of emitted
+// embers: they pop up, drift to a halt and fade out.
+func (p *fireworkPreset) OverLifeConfig() (size, alpha, speed curves.Curve) {
+	return curves.Keys(0, 0.6, 0.1, 1.3, 1, 0.2),
+		curves.FadeOutQuad,
+		curves.Range(1, 0.15, curves.OutQuad)
+}
+
+// SpriteConfig returns the sprite of emitted embers and their maximum
+// spin in degrees per second: twinkling four-pointed sparks.
+func (p *fireworkPreset) SpriteConfig() (*components.Sprite, float32) {
+	return components.NewSprite(SheetSpark).WithFrames(0, 4).WithFPS(12).WithScale(3), 90
+}
+
+// GradientConfig returns the color gradient for emitted embers.
+func (p *fireworkPreset) GradientConfig() *components.Gradient {
+	return FireGradient()
+}
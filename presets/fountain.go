@@ -37,6 +37,28 @@ func (p *fountainPreset) Palette() premium.ColorPalette {
 	return p.palette
 }
 
+// Background returns a gradient from dusk blue down to deep water.
+func (p *fountainPreset) Background() premium.Background {
+	return premium.GradientBackground(15, 25, 45, 5, 10, 20)
+}
+
+// PostEffects returns a soft vignette.
+func (p *fountainPreset) PostEffects() []premium.PostEffect {
+	return []premium.PostEffect{premium.Vignette(0.35, 0.5)}
+}
+
+// MetaballConfig returns the metaballs the water is drawn with, so
+// neighboring drops merge into streams.
+func (p *fountainPreset) MetaballConfig() *premium.Metaballs {
+	return premium.NewMetaballs().WithSpread(3.5)
+}
+
+// Resize keeps the spray at the bottom center of a window resized from
+// fromW × fromH to toW × toH.
+func (p *fountainPreset) Resize(em ecs.EntityManager, fromW, fromH, toW, toH float32) {
+	moveParticles(em, (toW-fromW)/2, toH-fromH)
+}
+
 func (p *fountainPreset) Apply(em ecs.EntityManager, cfg *config.Config) {
 	ClearParticles(em)
 
@@ -63,11 +85,11 @@ func (p *fountainPreset) Apply(em ecs.EntityManager, cfg *config.Config) {
 			er, eg, eb, ea = pal.EndR, pal.EndG, pal.EndB, pal.EndA
 		}
 
-		em.Add(ecs.NewEntity("", []ecs.Component{
+		em.Add(ecs.NewEntity(components.NewParticleID(), []ecs.Component{
 			components.NewPosition().With(x, y),
 			components.NewVelocity().With(vx, vy),
 			components.NewAcceleration().WithY(150),
-			components.NewColor().WithGradient(sr, sg, sb, sa, er, eg, eb, ea),
+			components.NewColor().WithGradient(sr, sg, sb, sa, er, eg, eb, ea).WithSpace(pal.Space),
 			components.NewLifetime().WithTTL(2.0 + rand.Float32()*2.0),
 			components.NewSize().WithRadius(3.0 + rand.Float32()*2.0).WithEndSize(1.0),
 			components.NewParticle(),
@@ -37,6 +37,33 @@ func (p *galaxyPreset) Palette() premium.ColorPalette {
 	return p.palette
 }
 
+// BlendMode returns the blend mode for this preset's particles, so
+// dense spiral arms glow brighter.
+func (p *galaxyPreset) BlendMode() components.BlendMode {
+	return components.BlendAdditive
+}
+
+// Background returns a deep-space starfield whose layers drift with the
+// camera.
+func (p *galaxyPreset) Background() premium.Background {
+	return premium.StarfieldBackground(400, 3, 5, 5, 20, 15, 5, 30)
+}
+
+// PostEffects returns a slight lens fringe and a vignette framing the
+// galaxy.
+func (p *galaxyPreset) PostEffects() []premium.PostEffect {
+	return []premium.PostEffect{
+		premium.ChromaticAberration(1.5),
+		premium.Vignette(0.5, 0.4),
+	}
+}
+
+// Resize keeps the spiral centered in a window resized from fromW × fromH
+// to toW × toH.
+func (p *galaxyPreset) Resize(em ecs.EntityManager, fromW, fromH, toW, toH float32) {
+	moveParticles(em, (toW-fromW)/2, (toH-fromH)/2)
+}
+
 func (p *galaxyPreset) Apply(em ecs.EntityManager, cfg *config.Config) {
 	ClearParticles(em)
 
@@ -66,11 +93,11 @@ func (p *galaxyPreset) Apply(em ecs.EntityManager, cfg *config.Config) {
 			er, eg, eb, ea = pal.EndR, pal.EndG, pal.EndB, pal.EndA
 		}
 
-		em.Add(ecs.NewEntity("", []ecs.Component{
+		em.Add(ecs.NewEntity(components.NewParticleID(), []ecs.Component{
 			components.NewPosition().With(x, y),
 			components.NewVelocity().With(vx, vy),
 			components.NewAcceleration(),
-			components.NewColor().WithGradient(sr, sg, sb, sa, er, eg, eb, ea),
+			components.NewColor().WithGradient(sr, sg, sb, sa, er, eg, eb, ea).WithSpace(pal.Space),
 			components.NewLifetime().WithTTL(8.0 + rand.Float32()*4.0),
 			components.NewSize().WithRadius(2.0 + rand.Float32()*2.0).WithEndSize(0.5),
 			components.NewParticle(),
@@ -0,0 +1,16 @@
+package presets
+
+import "github.com/deltatree/showcase/components"
+
+// FireGradient returns the color ramp of a burning ember: white hot at
+// birth, cooling through yellow, orange and red into grey smoke that fades
+// out.
+func FireGradient() *components.Gradient {
+	return components.NewGradient().
+		WithStop(0.0, 255, 255, 255, 255).
+		WithStop(0.08, 255, 240, 120, 255).
+		WithEasedStop(0.25, 255, 160, 30, 250, components.EaseOutQuad).
+		WithEasedStop(0.5, 210, 50, 15, 220, components.EaseInOutQuad).
+		WithEasedStop(0.7, 90, 85, 85, 140, components.EaseOutQuad).
+		WithStop(1.0, 60, 60, 60, 0)
+}
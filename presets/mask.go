@@ -0,0 +1,43 @@
+package presets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andygeiss/ecs"
+	"github.com/deltatree/showcase/components"
+	"github.com/deltatree/showcase/shapes"
+)
+
+// maskAttractorPrefix identifies attractors created by AddMaskAttractors.
+const maskAttractorPrefix = "mask-attractor-"
+
+// AddMaskAttractors creates one attractor entity per point, each with the
+// given mass. Combined with an emitter using the "mask" spawn pattern this
+// pulls particles onto the opaque pixels of a logo or text, producing a
+// logo reveal.
+//
+// Every attractor acts on every particle, so sample masks with a coarse
+// step (a few hundred points) to keep GravitySystem fast.
+func AddMaskAttractors(em ecs.EntityManager, points shapes.PointSet, mass float32) []*ecs.Entity {
+	entities := make([]*ecs.Entity, 0, len(points))
+	for i, p := range points {
+		e := ecs.NewEntity(fmt.Sprintf("%s%d", maskAttractorPrefix, i), []ecs.Component{
+			components.NewPosition().With(p.X, p.Y),
+			components.NewMass().WithValue(mass),
+			components.NewAttractor(),
+		})
+		entities = append(entities, e)
+	}
+	em.Add(entities...)
+	return entities
+}
+
+// RemoveMaskAttractors removes all attractors created by AddMaskAttractors.
+func RemoveMaskAttractors(em ecs.EntityManager) {
+	for _, e := range em.FilterByMask(components.MaskAttractor) {
+		if strings.HasPrefix(e.Id, maskAttractorPrefix) {
+			em.Remove(e)
+		}
+	}
+}
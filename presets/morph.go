@@ -0,0 +1,97 @@
+package presets
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/andygeiss/ecs"
+	"github.com/deltatree/showcase/components"
+	"github.com/deltatree/showcase/shapes"
+)
+
+// AssignTargets morphs existing particles into a figure. Each point of the
+// set becomes the Target of one particle, picked in random order so the
+// figure assembles from all directions. TargetSystem then steers the
+// particles into place with the given strength.
+//
+// If there are fewer particles than points only part of the figure is
+// formed; surplus particles keep moving freely. Returns the number of
+// particles that received a target.
+//
+// Example forming a star and releasing it three seconds later:
+//
+//	presets.AssignTargets(em, shapes.Star(640, 360, 200, 80, 5, 60), 6)
+//	// ...
+//	presets.ReleaseTargets(em, 400)
+func AssignTargets(em ecs.EntityManager, points shapes.PointSet, strength float32) int {
+	particles := em.FilterByMask(components.MaskParticle | components.MaskMovable | components.MaskAcceleration)
+	n := min(len(particles), len(points))
+
+	order := rand.Perm(len(particles))
+	for i := 0; i < n; i++ {
+		e := particles[order[i]]
+		p := points[i]
+
+		if t := e.Get(components.MaskTarget); t != nil {
+			target := t.(*components.Target)
+			target.X, target.Y = p.X, p.Y
+			target.Strength = strength
+			target.Active = true
+			continue
+		}
+		e.Add(components.NewTarget().With(p.X, p.Y).WithStrength(strength))
+		refresh(em, e)
+	}
+	return n
+}
+
+// ReleaseTargets dissolves a figure: all targets are removed and every
+// particle that held one is flung outwards from the figure's center with
+// a random speed up to burst pixels per second, returning it to the
+// regular particle field.
+func ReleaseTargets(em ecs.EntityManager, burst float32) {
+	held := em.FilterByMask(components.MaskTarget | components.MaskMovable)
+	if len(held) == 0 {
+		return
+	}
+
+	var cx, cy float32
+	for _, e := range held {
+		pos := e.Get(components.MaskPosition).(*components.Position)
+		cx += pos.X
+		cy += pos.Y
+	}
+	cx /= float32(len(held))
+	cy /= float32(len(held))
+
+	for _, e := range held {
+		pos := e.Get(components.MaskPosition).(*components.Position)
+		vel := e.Get(components.MaskVelocity).(*components.Velocity)
+
+		dx, dy := pos.X-cx, pos.Y-cy
+		dist := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+		if dist < 1 {
+			angle := rand.Float64() * 2 * math.Pi
+			dx, dy, dist = float32(math.Cos(angle)), float32(math.Sin(angle)), 1
+		}
+		speed := burst * (0.5 + rand.Float32()*0.5)
+		vel.X += dx / dist * speed
+		vel.Y += dy / dist * speed
+
+		e.Remove(components.MaskTarget)
+		refresh(em, e)
+	}
+}
+
+// refresher is implemented by entity managers that cache queries by mask
+// (systems.QueryCache) and must be told when an entity's components change.
+type refresher interface {
+	Refresh(e *ecs.Entity)
+}
+
+// refresh re-indexes e if em caches queries.
+func refresh(em ecs.EntityManager, e *ecs.Entity) {
+	if r, ok := em.(refresher); ok {
+		r.Refresh(e)
+	}
+}
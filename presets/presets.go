@@ -85,10 +85,12 @@ func GetPresetByName(name string) Preset {
 	return Registry[0]
 }
 
-// ClearParticles removes all particle entities from the entity manager.
+// ClearParticles removes all particle entities from the entity manager,
+// along with scenery a preset added, such as AddSkyline's skyline.
 func ClearParticles(em ecs.EntityManager) {
 	particles := em.FilterByMask(components.MaskParticle)
 	for _, p := range particles {
 		em.Remove(p)
 	}
+	clearScenery(em)
 }
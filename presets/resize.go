@@ -0,0 +1,36 @@
+package presets
+
+import (
+	"github.com/andygeiss/ecs"
+	"github.com/deltatree/showcase/components"
+)
+
+// Presets lay out their particles and scenery for the window size at the
+// time they are applied. After a resize, their Resize methods move what
+// they laid out to the new size instead of applying the preset again, so
+// particles and morph figures survive a window being dragged larger.
+
+// moveParticles moves all particles by (dx, dy). Their trails are cleared,
+// so the jump is not drawn as a streak.
+func moveParticles(em ecs.EntityManager, dx, dy float32) {
+	transformParticles(em, func(x, y float32) (float32, float32) {
+		return x + dx, y + dy
+	})
+}
+
+// scaleParticles scales the positions of all particles by (sx, sy).
+func scaleParticles(em ecs.EntityManager, sx, sy float32) {
+	transformParticles(em, func(x, y float32) (float32, float32) {
+		return x * sx, y * sy
+	})
+}
+
+func transformParticles(em ecs.EntityManager, f func(x, y float32) (float32, float32)) {
+	for _, e := range em.FilterByMask(components.MaskParticle | components.MaskPosition) {
+		pos := e.Get(components.MaskPosition).(*components.Position)
+		pos.X, pos.Y = f(pos.X, pos.Y)
+		if t := e.Get(components.MaskTrail); t != nil {
+			t.(*components.Trail).Reset()
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package presets
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"strings"
+
+	"github.com/andygeiss/ecs"
+	"github.com/deltatree/showcase/components"
+)
+
+// sceneryPrefix identifies scenery entities created by presets, which
+// ClearParticles removes together with the particles.
+const sceneryPrefix = "scenery-"
+
+// skylineID is the Id of the skyline added by AddSkyline.
+const skylineID = sceneryPrefix + "skyline"
+
+// Size of the skyline sheet in pixels.
+const (
+	skylineWidth  = 512
+	skylineHeight = 96
+)
+
+// renderSkyline renders a city skyline silhouette, white on transparent,
+// with its buildings standing on the bottom edge. The city is the same on
+// every run.
+func renderSkyline() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, skylineWidth, skylineHeight))
+	rng := rand.New(rand.NewSource(7))
+	fill := func(x0, y0, x1, y1 int) {
+		for y := max(y0, 0); y < min(y1, skylineHeight); y++ {
+			for x := max(x0, 0); x < min(x1, skylineWidth); x++ {
+				img.SetNRGBA(x, y, color.NRGBA{255, 255, 255, 255})
+			}
+		}
+	}
+	for x := 0; x < skylineWidth; {
+		w := 14 + rng.Intn(30)
+		top := skylineHeight - 24 - rng.Intn(skylineHeight-36)
+		fill(x, top, x+w-1, skylineHeight)
+		// Some towers carry an antenna
+		if rng.Intn(4) == 0 {
+			mid := x + w/2
+			fill(mid-1, top-10, mid+1, top)
+		}
+		x += w
+	}
+	return img
+}
+
+// AddSkyline adds a city skyline silhouette along the bottom of a width ×
+// height screen, in the background layer where lighting falls on it. The
+// color is its fully lit tint.
+func AddSkyline(em ecs.EntityManager, width, height float32, r, g, b uint8) *ecs.Entity {
+	e := ecs.NewEntity(skylineID, []ecs.Component{
+		components.NewPosition(),
+		components.NewColor().WithRGBA(r, g, b, 255),
+		components.NewSize(),
+		components.NewSprite(SheetSkyline),
+		components.NewLayer(components.LayerBackground),
+	})
+	layoutSkyline(e, width, height)
+	em.Add(e)
+	return e
+}
+
+// layoutSkyline stretches the skyline e along the bottom of a width ×
+// height screen.
+func layoutSkyline(e *ecs.Entity, width, height float32) {
+	// Sprites are scaled to a diameter of their longer side
+	h := width * skylineHeight / skylineWidth
+	e.Get(components.MaskPosition).(*components.Position).With(width/2, height-h/2)
+	e.Get(components.MaskSize).(*components.Size).WithRadius(width / 2)
+}
+
+// clearScenery removes the scenery entities created by presets.
+func clearScenery(em ecs.EntityManager) {
+	for _, e := range em.FilterByMask(components.MaskSprite) {
+		if strings.HasPrefix(e.Id, sceneryPrefix) {
+			em.Remove(e)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package presets
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Names of the built-in sprite sheets.
+const (
+	SheetSpark     = "spark"
+	SheetSmoke     = "smoke"
+	SheetSnowflake = "snowflake"
+	SheetLeaf      = "leaf"
+	SheetSkyline   = "skyline"
+)
+
+// spriteFrameSize is the edge length of a built-in sprite frame in pixels.
+const spriteFrameSize = 32
+
+// SpriteAtlas is a sprite sheet image split into equally sized frames.
+type SpriteAtlas struct {
+	Name                    string
+	Image                   image.Image
+	FrameWidth, FrameHeight int
+}
+
+// SpriteAtlases returns the built-in sprite sheets: a twinkling spark
+// (4 frames), a dissolving smoke puff (4 frames), a snowflake, a leaf and
+// a city skyline (see AddSkyline).
+// The sheets are white on transparent so particles tint them with their
+// color. They are generated, so the showcase ships without image files;
+// PNG atlases with the same layout can be loaded with
+// systems.LoadSpriteSheet instead.
+//
+// Register them with the render system before running the engine:
+//
+//	for _, a := range presets.SpriteAtlases() {
+//	    renderSystem.AddSpriteSheet(a.Name, systems.NewSpriteSheet(a.Image, a.FrameWidth, a.FrameHeight))
+//	}
+func SpriteAtlases() []SpriteAtlas {
+	return []SpriteAtlas{
+		{SheetSpark, renderSheet(4, spark), spriteFrameSize, spriteFrameSize},
+		{SheetSmoke, renderSheet(4, smoke), spriteFrameSize, spriteFrameSize},
+		{SheetSnowflake, renderSheet(1, snowflake), spriteFrameSize, spriteFrameSize},
+		{SheetLeaf, renderSheet(1, leaf), spriteFrameSize, spriteFrameSize},
+		{SheetSkyline, renderSkyline(), skylineWidth, skylineHeight},
+	}
+}
+
+// renderSheet renders frames side by side. shape returns the coverage
+// (0 to 1) of frame i at (x, y) in [-1, 1]².
+func renderSheet(frames int, shape func(i int, x, y float64) float64) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, frames*spriteFrameSize, spriteFrameSize))
+	for i := 0; i < frames; i++ {
+		for py := 0; py < spriteFrameSize; py++ {
+			for px := 0; px < spriteFrameSize; px++ {
+				x := (float64(px)+0.5)/spriteFrameSize*2 - 1
+				y := (float64(py)+0.5)/spriteFrameSize*2 - 1
+				a := math.Max(0, math.Min(1, shape(i, x, y)))
+				img.SetNRGBA(i*spriteFrameSize+px, py, color.NRGBA{255, 255, 255, uint8(a*255 + 0.5)})
+			}
+		}
+	}
+	return img
+}
+
+// spark is a four-pointed star whose arms pulse over the frames.
+func spark(i int, x, y float64) float64 {
+	arm := 0.7 + 0.25*math.Sin(float64(i)*math.Pi/2)
+	core := math.Exp(-(x*x + y*y) * 30)
+	h := math.Exp(-y*y*600) * math.Max(0, 1-math.Abs(x)/arm)
+	v := math.Exp(-x*x*600) * math.Max(0, 1-math.Abs(y)/arm)
+	return core + h + v
+}
+
+// smoke is a lumpy puff that grows and thins out over the frames.
+func smoke(i int, x, y float64) float64 {
+	t := float64(i) / 3
+	grow := 0.55 + 0.35*t
+	blobs := [...][3]float64{{0, 0, 1}, {-0.3, -0.2, 0.7}, {0.3, -0.15, 0.75}, {0.1, 0.3, 0.65}, {-0.25, 0.25, 0.6}}
+	var d float64
+	for _, b := range blobs {
+		dx, dy := x-b[0]*grow, y-b[1]*grow
+		r := 0.45 * b[2] * grow
+		d = math.Max(d, math.Exp(-(dx*dx+dy*dy)/(r*r)))
+	}
+	return d * (1 - 0.6*t)
+}
+
+// snowflake has six arms with two pairs of side branches each.
+func snowflake(_ int, x, y float64) float64 {
+	const width = 0.06
+	var dist = math.Inf(1)
+	for k := 0; k < 6; k++ {
+		a := float64(k) * math.Pi / 3
+		ca, sa := math.Cos(a), math.Sin(a)
+		dist = math.Min(dist, segmentDist(x, y, 0, 0, 0.9*ca, 0.9*sa))
+		for _, at := range [...]float64{0.4, 0.65} {
+			bx, by := at*ca, at*sa
+			l := 0.5 - at*0.3
+			for _, side := range [...]float64{-1, 1} {
+				b := a + side*math.Pi/4
+				dist = math.Min(dist, segmentDist(x, y, bx, by, bx+l*math.Cos(b), by+l*math.Sin(b)))
+			}
+		}
+	}
+	return (width - dist) / 0.04
+}
+
+// leaf is a pointed oval with a darker midrib and a short stem.
+func leaf(_ int, x, y float64) float64 {
+	// Oval narrowing to points at x = ±0.85
+	half := 0.42 * (1 - x*x/(0.85*0.85))
+	body := (half - math.Abs(y)) / 0.05
+	if math.Abs(x) > 0.85 {
+		body = 0
+	}
+	stem := (0.035 - segmentDist(x, y, -1, 0.1, -0.8, 0)) / 0.03
+	coverage := math.Max(math.Min(body, 1), stem)
+	if math.Abs(y) < 0.07 && math.Abs(x) < 0.8 {
+		coverage *= 0.6
+	}
+	return coverage
+}
+
+// segmentDist returns the distance from (px, py) to the segment from
+// (ax, ay) to (bx, by).
+func segmentDist(px, py, ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	t := ((px-ax)*dx + (py-ay)*dy) / (dx*dx + dy*dy)
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(px-(ax+t*dx), py-(ay+t*dy))
+}
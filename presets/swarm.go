@@ -37,6 +37,17 @@ func (p *swarmPreset) Palette() premium.ColorPalette {
 	return p.palette
 }
 
+// PostEffects returns a vignette that keeps the eye on the swarm.
+func (p *swarmPreset) PostEffects() []premium.PostEffect {
+	return []premium.PostEffect{premium.Vignette(0.45, 0.45)}
+}
+
+// Resize keeps the swarm centered in a window resized from fromW × fromH
+// to toW × toH.
+func (p *swarmPreset) Resize(em ecs.EntityManager, fromW, fromH, toW, toH float32) {
+	moveParticles(em, (toW-fromW)/2, (toH-fromH)/2)
+}
+
 func (p *swarmPreset) Apply(em ecs.EntityManager, cfg *config.Config) {
 	ClearParticles(em)
 
@@ -63,11 +74,11 @@ func (p *swarmPreset) Apply(em ecs.EntityManager, cfg *config.Config) {
 			er, eg, eb, ea = pal.EndR, pal.EndG, pal.EndB, pal.EndA
 		}
 
-		em.Add(ecs.NewEntity("", []ecs.Component{
+		em.Add(ecs.NewEntity(components.NewParticleID(), []ecs.Component{
 			components.NewPosition().With(x, y),
 			components.NewVelocity().With(vx, vy),
 			components.NewAcceleration(),
-			components.NewColor().WithGradient(sr, sg, sb, sa, er, eg, eb, ea),
+			components.NewColor().WithGradient(sr, sg, sb, sa, er, eg, eb, ea).WithSpace(pal.Space),
 			components.NewLifetime().WithTTL(10.0 + rand.Float32()*5.0),
 			components.NewSize().WithRadius(3.0 + rand.Float32()*2.0).WithEndSize(1.0),
 			components.NewParticle(),
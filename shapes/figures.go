@@ -0,0 +1,90 @@
+package shapes
+
+import "math"
+
+// white is the color of generated figure points.
+func white(x, y float32) Point {
+	return Point{X: x, Y: y, R: 255, G: 255, B: 255, A: 255}
+}
+
+// Circle returns n points evenly spaced on a circle of radius r around
+// (cx, cy).
+func Circle(cx, cy, r float32, n int) PointSet {
+	points := make(PointSet, 0, n)
+	for i := 0; i < n; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		points = append(points, white(
+			cx+r*float32(math.Cos(a)),
+			cy+r*float32(math.Sin(a)),
+		))
+	}
+	return points
+}
+
+// Line returns n points evenly spaced from (x1, y1) to (x2, y2),
+// including both end points.
+func Line(x1, y1, x2, y2 float32, n int) PointSet {
+	if n == 1 {
+		return PointSet{white(x1, y1)}
+	}
+	points := make(PointSet, 0, n)
+	for i := 0; i < n; i++ {
+		t := float32(i) / float32(n-1)
+		points = append(points, white(x1+(x2-x1)*t, y1+(y2-y1)*t))
+	}
+	return points
+}
+
+// Polygon returns the outline of a closed polygon through the given
+// vertices (x0, y0, x1, y1, ...), with perEdge points on every edge. A
+// perEdge below 1 is treated as 1, giving just the vertices.
+func Polygon(perEdge int, vertices ...float32) PointSet {
+	if perEdge < 1 {
+		perEdge = 1
+	}
+	n := len(vertices) / 2
+	points := make(PointSet, 0, n*perEdge)
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		x1, y1 := vertices[2*i], vertices[2*i+1]
+		x2, y2 := vertices[2*j], vertices[2*j+1]
+		for k := 0; k < perEdge; k++ {
+			t := float32(k) / float32(perEdge)
+			points = append(points, white(x1+(x2-x1)*t, y1+(y2-y1)*t))
+		}
+	}
+	return points
+}
+
+// RegularPolygon returns the outline of a regular polygon with the given
+// number of sides and circumradius r, with perEdge points on every edge.
+func RegularPolygon(cx, cy, r float32, sides, perEdge int) PointSet {
+	vertices := make([]float32, 0, 2*sides)
+	for i := 0; i < sides; i++ {
+		a := 2*math.Pi*float64(i)/float64(sides) - math.Pi/2
+		vertices = append(vertices,
+			cx+r*float32(math.Cos(a)),
+			cy+r*float32(math.Sin(a)),
+		)
+	}
+	return Polygon(perEdge, vertices...)
+}
+
+// Star returns the outline of a star with the given number of spikes,
+// alternating between the outer and inner radius, with perEdge points on
+// every edge.
+func Star(cx, cy, outer, inner float32, spikes, perEdge int) PointSet {
+	vertices := make([]float32, 0, 4*spikes)
+	for i := 0; i < 2*spikes; i++ {
+		r := outer
+		if i%2 == 1 {
+			r = inner
+		}
+		a := math.Pi*float64(i)/float64(spikes) - math.Pi/2
+		vertices = append(vertices,
+			cx+r*float32(math.Cos(a)),
+			cy+r*float32(math.Sin(a)),
+		)
+	}
+	return Polygon(perEdge, vertices...)
+}
@@ -0,0 +1,127 @@
+// Package shapes provides point sets sampled from images and geometric
+// figures for Particle Symphony. It has no rendering dependencies; text
+// is rasterized by the caller and sampled like any other image.
+//
+// A PointSet is a list of positions with an optional color per point.
+// Point sets are used as spawn masks by EmitterSystem (particles spawn on
+// the opaque pixels of a logo) and as attractor or morph-target layouts
+// by presets.
+//
+// # Usage
+//
+// Sample a PNG logo every 4 pixels and center it on screen:
+//
+//	points, err := shapes.LoadPNG("logo.png", 4, 128)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	points = points.Centered(640, 360)
+package shapes
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+)
+
+// Point is a single sample of a point set with its source color.
+type Point struct {
+	// X, Y are the point coordinates in pixels.
+	X, Y float32
+	// R, G, B, A are the color sampled from the source (white for
+	// generated figures).
+	R, G, B, A uint8
+}
+
+// PointSet is an ordered list of points.
+type PointSet []Point
+
+// FromImage samples img on a grid of the given step and returns one point
+// for every pixel whose alpha is at least threshold. Step values below 1
+// are treated as 1.
+//
+// Colors are returned un-premultiplied so they can be used directly as
+// particle start colors.
+func FromImage(img image.Image, step int, threshold uint8) PointSet {
+	if step < 1 {
+		step = 1
+	}
+	b := img.Bounds()
+	points := PointSet{}
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < threshold || c.A == 0 {
+				continue
+			}
+			points = append(points, Point{
+				X: float32(x - b.Min.X),
+				Y: float32(y - b.Min.Y),
+				R: c.R, G: c.G, B: c.B, A: c.A,
+			})
+		}
+	}
+	return points
+}
+
+// LoadPNG decodes a PNG file and samples it with FromImage.
+func LoadPNG(path string, step int, threshold uint8) (PointSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return FromImage(img, step, threshold), nil
+}
+
+// Bounds returns the axis-aligned bounding box of the point set.
+// An empty set returns all zeros.
+func (ps PointSet) Bounds() (minX, minY, maxX, maxY float32) {
+	if len(ps) == 0 {
+		return 0, 0, 0, 0
+	}
+	minX, minY = ps[0].X, ps[0].Y
+	maxX, maxY = ps[0].X, ps[0].Y
+	for _, p := range ps[1:] {
+		minX = min(minX, p.X)
+		minY = min(minY, p.Y)
+		maxX = max(maxX, p.X)
+		maxY = max(maxY, p.Y)
+	}
+	return minX, minY, maxX, maxY
+}
+
+// Translated returns a copy of the point set moved by (dx, dy).
+func (ps PointSet) Translated(dx, dy float32) PointSet {
+	out := make(PointSet, len(ps))
+	for i, p := range ps {
+		p.X += dx
+		p.Y += dy
+		out[i] = p
+	}
+	return out
+}
+
+// Scaled returns a copy of the point set scaled by f around the origin.
+func (ps PointSet) Scaled(f float32) PointSet {
+	out := make(PointSet, len(ps))
+	for i, p := range ps {
+		p.X *= f
+		p.Y *= f
+		out[i] = p
+	}
+	return out
+}
+
+// Centered returns a copy of the point set whose bounding box is centered
+// on (cx, cy).
+func (ps PointSet) Centered(cx, cy float32) PointSet {
+	minX, minY, maxX, maxY := ps.Bounds()
+	return ps.Translated(cx-(minX+maxX)/2, cy-(minY+maxY)/2)
+}
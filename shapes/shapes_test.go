@@ -0,0 +1,164 @@
+package shapes
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(1, 1, color.NRGBA{255, 0, 0, 255})
+	img.Set(2, 2, color.NRGBA{0, 255, 0, 100})
+	img.Set(3, 3, color.NRGBA{0, 0, 255, 10})
+	return img
+}
+
+func TestFromImage_Threshold(t *testing.T) {
+	points := FromImage(testImage(), 1, 50)
+	if len(points) != 2 {
+		t.Fatalf("FromImage() returned %d points, want 2", len(points))
+	}
+	if points[0].X != 1 || points[0].Y != 1 || points[0].R != 255 {
+		t.Errorf("first point = %+v, want red at (1, 1)", points[0])
+	}
+	if points[1].G != 255 || points[1].A != 100 {
+		t.Errorf("second point = %+v, want green with alpha 100", points[1])
+	}
+}
+
+func TestFromImage_Step(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	if got := len(FromImage(img, 2, 1)); got != 16 {
+		t.Errorf("FromImage(step=2) returned %d points, want 16", got)
+	}
+	if got := len(FromImage(img, 0, 1)); got != 64 {
+		t.Errorf("FromImage(step=0) returned %d points, want 64", got)
+	}
+}
+
+func TestLoadPNG(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mask.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, testImage()); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	points, err := LoadPNG(path, 1, 50)
+	if err != nil {
+		t.Fatalf("LoadPNG() error = %v", err)
+	}
+	if len(points) != 2 {
+		t.Errorf("LoadPNG() returned %d points, want 2", len(points))
+	}
+}
+
+func TestLoadPNG_MissingFile(t *testing.T) {
+	if _, err := LoadPNG("does-not-exist.png", 1, 1); err == nil {
+		t.Error("LoadPNG() on missing file should return error")
+	}
+}
+
+func TestPointSet_Transforms(t *testing.T) {
+	ps := PointSet{{X: 0, Y: 0}, {X: 10, Y: 20}}
+
+	minX, minY, maxX, maxY := ps.Bounds()
+	if minX != 0 || minY != 0 || maxX != 10 || maxY != 20 {
+		t.Errorf("Bounds() = (%v, %v, %v, %v), want (0, 0, 10, 20)", minX, minY, maxX, maxY)
+	}
+
+	c := ps.Centered(100, 100)
+	if c[0].X != 95 || c[0].Y != 90 || c[1].X != 105 || c[1].Y != 110 {
+		t.Errorf("Centered() = %+v", c)
+	}
+	if ps[0].X != 0 {
+		t.Error("Centered() should not modify the original set")
+	}
+
+	s := ps.Scaled(2)
+	if s[1].X != 20 || s[1].Y != 40 {
+		t.Errorf("Scaled(2) = %+v", s[1])
+	}
+}
+
+func TestPointSet_EmptyBounds(t *testing.T) {
+	var ps PointSet
+	minX, minY, maxX, maxY := ps.Bounds()
+	if minX != 0 || minY != 0 || maxX != 0 || maxY != 0 {
+		t.Error("Bounds() of empty set should be zero")
+	}
+}
+
+func TestCircle(t *testing.T) {
+	points := Circle(100, 100, 50, 4)
+	if len(points) != 4 {
+		t.Fatalf("Circle() returned %d points, want 4", len(points))
+	}
+	for _, p := range points {
+		dx, dy := float64(p.X-100), float64(p.Y-100)
+		if d := math.Sqrt(dx*dx + dy*dy); math.Abs(d-50) > 0.01 {
+			t.Errorf("point (%v, %v) is %v from center, want 50", p.X, p.Y, d)
+		}
+		if p.A != 255 {
+			t.Errorf("figure points should be opaque, got alpha %d", p.A)
+		}
+	}
+}
+
+func TestLine(t *testing.T) {
+	points := Line(0, 0, 10, 0, 3)
+	if len(points) != 3 {
+		t.Fatalf("Line() returned %d points, want 3", len(points))
+	}
+	if points[0].X != 0 || points[1].X != 5 || points[2].X != 10 {
+		t.Errorf("Line() = %v, %v, %v; want 0, 5, 10", points[0].X, points[1].X, points[2].X)
+	}
+	if got := len(Line(1, 2, 3, 4, 1)); got != 1 {
+		t.Errorf("Line(n=1) returned %d points, want 1", got)
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	points := Polygon(2, 0, 0, 10, 0, 10, 10, 0, 10)
+	if len(points) != 8 {
+		t.Fatalf("Polygon() returned %d points, want 8", len(points))
+	}
+	if points[1].X != 5 || points[1].Y != 0 {
+		t.Errorf("edge midpoint = (%v, %v), want (5, 0)", points[1].X, points[1].Y)
+	}
+}
+
+func TestPolygon_PerEdgeBelowOne(t *testing.T) {
+	for _, perEdge := range []int{0, -3} {
+		points := Polygon(perEdge, 0, 0, 10, 0, 10, 10)
+		if len(points) != 3 {
+			t.Errorf("Polygon(%d) returned %d points, want the 3 vertices", perEdge, len(points))
+		}
+	}
+}
+
+func TestRegularPolygonAndStar(t *testing.T) {
+	if got := len(RegularPolygon(0, 0, 10, 6, 3)); got != 18 {
+		t.Errorf("RegularPolygon() returned %d points, want 18", got)
+	}
+	star := Star(0, 0, 10, 5, 5, 4)
+	if len(star) != 40 {
+		t.Errorf("Star() returned %d points, want 40", len(star))
+	}
+	if star[0].Y > -9.99 {
+		t.Errorf("first star spike should point up, got (%v, %v)", star[0].X, star[0].Y)
+	}
+}
@@ -0,0 +1,228 @@
+package systems
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/deltatree/showcase/premium"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// star is one star of a starfield. Positions are fractions of the screen
+// size, so the field survives window resizes.
+type star struct {
+	x, y   float32
+	depth  float32 // Parallax factor, 1 for the nearest layer
+	radius float32
+	phase  float32 // Twinkle phase in radians
+}
+
+// generateStars spreads n stars over layers parallax layers. Nearer layers
+// have bigger stars and move more with the camera. The field depends only
+// on n and layers, so switching back to a preset shows the same sky.
+func generateStars(n, layers int) []star {
+	layers = max(layers, 1)
+	rng := rand.New(rand.NewSource(int64(n*31 + layers)))
+	stars := make([]star, n)
+	for i := range stars {
+		layer := i % layers
+		depth := float32(layer+1) / float32(layers)
+		stars[i] = star{
+			x:      rng.Float32(),
+			y:      rng.Float32(),
+			depth:  depth,
+			radius: 0.5 + depth*rng.Float32()*1.2,
+			phase:  rng.Float32() * 2 * math.Pi,
+		}
+	}
+	return stars
+}
+
+// position returns the screen position of the star on a width × height
+// screen with the camera panned by (shiftX, shiftY), wrapping around the
+// screen edges.
+func (s star) position(shiftX, shiftY, width, height float32) (float32, float32) {
+	wrap := func(v, size float32) float32 {
+		v = float32(math.Mod(float64(v), float64(size)))
+		if v < 0 {
+			v += size
+		}
+		return v
+	}
+	return wrap(s.x*width-shiftX*s.depth, width), wrap(s.y*height-shiftY*s.depth, height)
+}
+
+// twinkle returns the star's brightness (0.4 to 1.0) at time t in seconds.
+func (s star) twinkle(t float32) float32 {
+	return 0.7 + 0.3*float32(math.Sin(float64(t*(1+s.depth)+s.phase)))
+}
+
+// BackgroundRenderer draws the scenery behind the particles as described
+// by a premium.Background: a solid color, a vertical gradient, a parallax
+// starfield or an image.
+//
+// For persistent backgrounds the scene is drawn into an accumulation
+// texture that is not cleared between frames; BeginPersistent covers the
+// previous frame with the background at the fade alpha, so particles leave
+// light trails that fade into it.
+//
+// GPU resources (the image and the accumulation texture) are created on
+// first use and released by Unload.
+type BackgroundRenderer struct {
+	background    premium.Background
+	stars         []star
+	width, height int32
+
+	image       rl.Texture2D
+	imageLoaded bool
+
+	accum       rl.RenderTexture2D
+	accumLoaded bool
+	accumFresh  bool // The accumulation texture must be cleared first
+}
+
+// NewBackgroundRenderer creates a renderer with the default background for
+// a screen of the given size.
+func NewBackgroundRenderer(width, height int32) *BackgroundRenderer {
+	r := &BackgroundRenderer{width: width, height: height}
+	r.SetBackground(premium.DefaultBackground)
+	return r
+}
+
+// SetBackground switches to another background.
+func (r *BackgroundRenderer) SetBackground(bg premium.Background) {
+	if bg.ImagePath != r.background.ImagePath {
+		r.unloadImage()
+	}
+	r.background = bg
+	r.stars = nil
+	if bg.Kind == premium.BackgroundStarfield {
+		r.stars = generateStars(bg.Stars, bg.StarLayers)
+	}
+	r.accumFresh = true
+}
+
+// Background returns the current background.
+func (r *BackgroundRenderer) Background() premium.Background {
+	return r.background
+}
+
+// Color returns the color the screen is cleared with.
+func (r *BackgroundRenderer) Color() color.RGBA {
+	bg := r.background
+	return rl.NewColor(bg.TopR, bg.TopG, bg.TopB, 255)
+}
+
+// Persistent reports whether frames accumulate instead of being cleared.
+func (r *BackgroundRenderer) Persistent() bool {
+	return r.background.Persistence > 0
+}
+
+// Draw draws the background at the given opacity on the current target,
+// with the camera panned by (shiftX, shiftY); t is the time in seconds
+// for twinkling stars. The target is expected to be cleared with Color
+// when alpha is 255.
+func (r *BackgroundRenderer) Draw(shiftX, shiftY, t float32, alpha uint8) {
+	bg := r.background
+	w, h := float32(r.width), float32(r.height)
+
+	switch bg.Kind {
+	case premium.BackgroundSolid:
+		if alpha < 255 {
+			rl.DrawRectangle(0, 0, r.width, r.height, rl.NewColor(bg.TopR, bg.TopG, bg.TopB, alpha))
+		}
+		return
+	case premium.BackgroundImage:
+		if r.loadImage() {
+			tw, th := float32(r.image.Width), float32(r.image.Height)
+			// Cover the screen, cropping the overhanging side
+			scale := max(w/tw, h/th)
+			dst := rl.NewRectangle((w-tw*scale)/2, (h-th*scale)/2, tw*scale, th*scale)
+			rl.DrawTexturePro(r.image, rl.NewRectangle(0, 0, tw, th), dst, rl.Vector2{}, 0, rl.NewColor(255, 255, 255, alpha))
+			return
+		}
+	}
+
+	rl.DrawRectangleGradientV(0, 0, r.width, r.height,
+		rl.NewColor(bg.TopR, bg.TopG, bg.TopB, alpha),
+		rl.NewColor(bg.BottomR, bg.BottomG, bg.BottomB, alpha))
+
+	for _, s := range r.stars {
+		x, y := s.position(shiftX, shiftY, w, h)
+		a := uint8(float32(alpha) * s.twinkle(t))
+		rl.DrawCircleV(rl.NewVector2(x, y), s.radius, rl.NewColor(255, 255, 255, a))
+	}
+}
+
+// loadImage loads the image texture once and reports whether it is usable.
+func (r *BackgroundRenderer) loadImage() bool {
+	if !r.imageLoaded {
+		r.image = rl.LoadTexture(r.background.ImagePath)
+		r.imageLoaded = true
+	}
+	return rl.IsTextureValid(r.image)
+}
+
+func (r *BackgroundRenderer) unloadImage() {
+	if r.imageLoaded {
+		if rl.IsTextureValid(r.image) {
+			rl.UnloadTexture(r.image)
+		}
+		r.imageLoaded = false
+	}
+}
+
+// BeginPersistent redirects drawing into the accumulation texture and
+// fades the previous frame into the background, drawn by draw at the given
+// opacity: Draw, or the lit background of LightRenderer. Every call must
+// be paired with EndPersistent.
+func (r *BackgroundRenderer) BeginPersistent(draw func(alpha uint8)) {
+	if !r.accumLoaded {
+		r.accum = rl.LoadRenderTexture(r.width, r.height)
+		r.accumLoaded = true
+		r.accumFresh = true
+	}
+	rl.BeginTextureMode(r.accum)
+	if r.accumFresh {
+		rl.ClearBackground(r.Color())
+		draw(255)
+		r.accumFresh = false
+	}
+	draw(r.background.FadeAlpha())
+}
+
+// EndPersistent finishes the frame and returns the accumulated scene.
+func (r *BackgroundRenderer) EndPersistent() rl.RenderTexture2D {
+	rl.EndTextureMode()
+	return r.accum
+}
+
+// Resize sets the screen size. The accumulated trails are dropped.
+func (r *BackgroundRenderer) Resize(width, height int32) {
+	r.width, r.height = width, height
+	r.unloadAccum()
+}
+
+func (r *BackgroundRenderer) unloadAccum() {
+	if r.accumLoaded {
+		rl.UnloadRenderTexture(r.accum)
+		r.accumLoaded = false
+	}
+}
+
+// Unload releases the GPU resources.
+func (r *BackgroundRenderer) Unload() {
+	r.unloadImage()
+	r.unloadAccum()
+}
+
+// copyTarget draws a render texture over the screen, replacing the pixels
+// beneath. Fading leaves the accumulated alpha below 1, which regular
+// alpha blending would mix with the screen.
+func copyTarget(target rl.RenderTexture2D, w, h float32) {
+	rl.SetBlendFactors(rl.One, rl.Zero, rl.FuncAdd)
+	rl.BeginBlendMode(rl.BlendCustom)
+	drawTarget(target, w, h)
+	rl.EndBlendMode()
+}
@@ -0,0 +1,329 @@
+package systems
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// particleDrawer draws the shapes particles are made of. immediateDrawer
+// issues raylib draw calls right away, BatchRenderer queues textured quads
+// and submits them in one draw call per run of quads sharing a texture.
+type particleDrawer interface {
+	// Circle draws a filled circle centered at (x, y).
+	Circle(x, y, radius float32, r, g, b, a uint8)
+	// Sprite draws a frame of sheet centered at (x, y), scaled to a
+	// diameter of 2 × radius and rotated by angle degrees.
+	Sprite(sheet *SpriteSheet, frame int, x, y, radius, angle float32, r, g, b, a uint8)
+}
+
+// raylib's default render batch holds 8192 quads; DrawCircle emits a circle
+// as 36 segments drawn as 18 quads.
+const (
+	defaultBatchVertices = 8192 * 4
+	circleVertices       = 18 * 4
+	spriteVertices       = 4
+)
+
+// drawCounter estimates the draw calls raylib's default render batch
+// issues: a new draw starts whenever the texture changes or the batch is
+// full.
+type drawCounter struct {
+	draws    int
+	vertices int
+	texture  *SpriteSheet // nil is raylib's shapes texture
+}
+
+// add records vertices drawn with the texture of sheet.
+func (c *drawCounter) add(sheet *SpriteSheet, vertices int) {
+	if c.draws == 0 || sheet != c.texture || c.vertices+vertices > defaultBatchVertices {
+		c.draws++
+		c.vertices = 0
+		c.texture = sheet
+	}
+	c.vertices += vertices
+}
+
+// reset starts a new frame.
+func (c *drawCounter) reset() {
+	*c = drawCounter{}
+}
+
+// immediateDrawer draws each shape with its own raylib call, counting the
+// draw calls that result.
+type immediateDrawer struct {
+	counter drawCounter
+}
+
+// immediate draws the shapes of the public per-particle Render methods.
+var immediate immediateDrawer
+
+func (d *immediateDrawer) Circle(x, y, radius float32, r, g, b, a uint8) {
+	rl.DrawCircle(int32(x), int32(y), radius, rl.NewColor(r, g, b, a))
+	d.counter.add(nil, circleVertices)
+}
+
+func (d *immediateDrawer) Sprite(sheet *SpriteSheet, frame int, x, y, radius, angle float32, r, g, b, a uint8) {
+	drawSprite(sheet, frame, x, y, radius, angle, r, g, b, a)
+	d.counter.add(sheet, spriteVertices)
+}
+
+// QuadBatch is a vertex buffer of textured quads sharing the texture of
+// one sprite sheet. Quads are stored as four corner positions, four
+// texture coordinates and one color each.
+type QuadBatch struct {
+	sheet     *SpriteSheet
+	texW      float32 // Texture size, to normalize texture coordinates
+	texH      float32
+	positions []float32 // x, y of the four corners
+	texcoords []float32 // u, v of the four corners
+	colors    []uint8   // r, g, b, a
+}
+
+// NewQuadBatch creates an empty batch drawing with the texture of sheet.
+func NewQuadBatch(sheet *SpriteSheet) *QuadBatch {
+	b := sheet.image.Bounds()
+	return &QuadBatch{sheet: sheet, texW: float32(b.Dx()), texH: float32(b.Dy())}
+}
+
+// Add appends a w × h quad centered at (x, y), rotated by angle degrees,
+// showing the src rectangle of the sheet tinted with the color.
+func (q *QuadBatch) Add(x, y, w, h, angle float32, src rl.Rectangle, r, g, b, a uint8) {
+	hw, hh := w/2, h/2
+	// Corners counter-clockwise from top left, as rlgl expects
+	if angle == 0 {
+		q.positions = append(q.positions, x-hw, y-hh, x-hw, y+hh, x+hw, y+hh, x+hw, y-hh)
+	} else {
+		s, c := math.Sincos(float64(angle) * math.Pi / 180)
+		sin, cos := float32(s), float32(c)
+		// Rotated half extents along the quad's x and y axes
+		xx, xy := hw*cos, hw*sin
+		yx, yy := -hh*sin, hh*cos
+		q.positions = append(q.positions,
+			x-xx-yx, y-xy-yy,
+			x-xx+yx, y-xy+yy,
+			x+xx+yx, y+xy+yy,
+			x+xx-yx, y+xy-yy,
+		)
+	}
+
+	u0, v0 := src.X/q.texW, src.Y/q.texH
+	u1, v1 := (src.X+src.Width)/q.texW, (src.Y+src.Height)/q.texH
+	q.texcoords = append(q.texcoords, u0, v0, u0, v1, u1, v1, u1, v0)
+	q.colors = append(q.colors, r, g, b, a)
+}
+
+// Circle appends a circle, drawn with the whole sheet as a disc texture.
+func (q *QuadBatch) Circle(x, y, radius float32, r, g, b, a uint8) {
+	q.positions = append(q.positions, x-radius, y-radius, x-radius, y+radius, x+radius, y+radius, x+radius, y-radius)
+	q.texcoords = append(q.texcoords, 0, 0, 0, 1, 1, 1, 1, 0)
+	q.colors = append(q.colors, r, g, b, a)
+}
+
+// Len returns the number of quads in the batch.
+func (q *QuadBatch) Len() int {
+	return len(q.colors) / 4
+}
+
+// Reset empties the batch, keeping its buffers.
+func (q *QuadBatch) Reset() {
+	q.positions = q.positions[:0]
+	q.texcoords = q.texcoords[:0]
+	q.colors = q.colors[:0]
+}
+
+// discSize is the edge length of the disc texture circles are drawn with.
+const discSize = 64
+
+// newDiscSheet creates a white anti-aliased disc on transparent, used as
+// the texture of batched circles.
+func newDiscSheet() *SpriteSheet {
+	img := image.NewNRGBA(image.Rect(0, 0, discSize, discSize))
+	const c = discSize / 2
+	for y := 0; y < discSize; y++ {
+		for x := 0; x < discSize; x++ {
+			dx, dy := float64(x)+0.5-c, float64(y)+0.5-c
+			// One pixel wide edge
+			a := math.Max(0, math.Min(1, c-math.Hypot(dx, dy)))
+			img.SetNRGBA(x, y, color.NRGBA{255, 255, 255, uint8(a*255 + 0.5)})
+		}
+	}
+	return NewSpriteSheet(img, discSize, discSize)
+}
+
+// Limits of the render batch BatchRenderer submits quads with. It grows to
+// the largest batch seen; beyond maxBatchQuads rlgl splits the batch.
+const (
+	minBatchQuads = 8192
+	maxBatchQuads = 1 << 17
+)
+
+// quadRun is a range of consecutive quads of a QuadBatch, drawn together.
+type quadRun struct {
+	batch        *QuadBatch
+	start, count int
+}
+
+// BatchRenderer draws particles as textured quads collected in one
+// QuadBatch per texture: circles (including glow layers and motion blur
+// samples) use a disc texture, sprites their sheet. Flush streams the
+// quads into a dedicated rlgl render batch sized to hold them and draws
+// each run of quads sharing a texture with a single draw call, instead of
+// the dozens of draws raylib's default batch needs for thousands of
+// DrawCircle calls.
+//
+// Quads are drawn in the order they were added: a new run starts whenever
+// the texture changes, as in raylib's default batch, so circles and
+// sprites keep their Z order.
+type BatchRenderer struct {
+	circles *QuadBatch
+	sprites map[*SpriteSheet]*QuadBatch
+	runs    []quadRun
+
+	// batch is handed to rlgl by pointer while drawing, so it lives on
+	// the heap for the lifetime of the renderer.
+	batch    *rl.RenderBatch
+	capacity int
+	draws    int
+}
+
+// NewBatchRenderer creates a batch renderer. GPU resources are created on
+// the first Flush.
+func NewBatchRenderer() *BatchRenderer {
+	return &BatchRenderer{
+		circles: NewQuadBatch(newDiscSheet()),
+		sprites: make(map[*SpriteSheet]*QuadBatch),
+	}
+}
+
+// Circle queues a filled circle.
+func (r *BatchRenderer) Circle(x, y, radius float32, cr, cg, cb, ca uint8) {
+	r.extend(r.circles)
+	r.circles.Circle(x, y, radius, cr, cg, cb, ca)
+}
+
+// Sprite queues a sprite frame, keeping the frame's aspect ratio.
+func (r *BatchRenderer) Sprite(sheet *SpriteSheet, frame int, x, y, radius, angle float32, cr, cg, cb, ca uint8) {
+	q, ok := r.sprites[sheet]
+	if !ok {
+		q = NewQuadBatch(sheet)
+		r.sprites[sheet] = q
+	}
+	r.extend(q)
+	src := sheet.FrameRect(frame)
+	scale := 2 * radius / max(src.Width, src.Height)
+	q.Add(x, y, src.Width*scale, src.Height*scale, angle, src, cr, cg, cb, ca)
+}
+
+// extend adds a quad of q to the last run, or starts a new run if the
+// last one draws with another texture.
+func (r *BatchRenderer) extend(q *QuadBatch) {
+	if n := len(r.runs); n > 0 && r.runs[n-1].batch == q {
+		r.runs[n-1].count++
+		return
+	}
+	r.runs = append(r.runs, quadRun{batch: q, start: q.Len(), count: 1})
+}
+
+// Len returns the number of queued quads.
+func (r *BatchRenderer) Len() int {
+	n := 0
+	for _, run := range r.runs {
+		n += run.count
+	}
+	return n
+}
+
+// Flush draws and empties all batches. It must be called inside
+// rl.BeginDrawing, before the blend mode changes.
+func (r *BatchRenderer) Flush() {
+	for _, run := range r.runs {
+		r.submit(run)
+	}
+	r.reset()
+}
+
+// reset empties the runs and batches, keeping their buffers.
+func (r *BatchRenderer) reset() {
+	r.runs = r.runs[:0]
+	r.circles.Reset()
+	for _, q := range r.sprites {
+		q.Reset()
+	}
+}
+
+// submit draws one run with a single draw call.
+func (r *BatchRenderer) submit(run quadRun) {
+	q := run.batch
+	r.reserve(run.count)
+
+	// Drawing with our batch first flushes raylib's default batch, so
+	// earlier shapes stay underneath
+	rl.SetRenderBatchActive(r.batch)
+	rl.SetTexture(q.sheet.Texture().ID)
+	rl.Begin(rl.Quads)
+	for i := run.start; i < run.start+run.count; i++ {
+		rl.Color4ub(q.colors[i*4], q.colors[i*4+1], q.colors[i*4+2], q.colors[i*4+3])
+		for k := i * 8; k < i*8+8; k += 2 {
+			rl.TexCoord2f(q.texcoords[k], q.texcoords[k+1])
+			rl.Vertex2f(q.positions[k], q.positions[k+1])
+		}
+	}
+	rl.End()
+	rl.SetTexture(0)
+	rl.SetRenderBatchActive(nil)
+
+	r.draws += batchDraws(run.count, r.capacity)
+}
+
+// reserve makes sure the render batch holds n quads, up to maxBatchQuads.
+func (r *BatchRenderer) reserve(n int) {
+	capacity := batchCapacity(r.capacity, n)
+	if r.batch != nil {
+		if capacity == r.capacity {
+			return
+		}
+		rl.UnloadRenderBatch(*r.batch)
+	}
+	batch := rl.LoadRenderBatch(1, int32(capacity))
+	r.batch = &batch
+	r.capacity = capacity
+}
+
+// batchCapacity returns the render batch size for n quads, doubling the
+// current size from minBatchQuads up to maxBatchQuads.
+func batchCapacity(current, n int) int {
+	capacity := max(current, minBatchQuads)
+	for capacity < n && capacity < maxBatchQuads {
+		capacity *= 2
+	}
+	return capacity
+}
+
+// batchDraws returns the draw calls needed for n quads in a render batch
+// holding capacity quads.
+func batchDraws(n, capacity int) int {
+	return (n + capacity - 1) / capacity
+}
+
+// DrawCalls returns the draw calls issued since the last ResetStats.
+func (r *BatchRenderer) DrawCalls() int {
+	return r.draws
+}
+
+// ResetStats resets the draw call count, typically once per frame.
+func (r *BatchRenderer) ResetStats() {
+	r.draws = 0
+}
+
+// Unload releases the render batch and the disc texture.
+func (r *BatchRenderer) Unload() {
+	if r.batch != nil {
+		rl.UnloadRenderBatch(*r.batch)
+		r.batch = nil
+		r.capacity = 0
+	}
+	r.circles.sheet.Unload()
+}
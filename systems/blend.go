@@ -0,0 +1,34 @@
+package systems
+
+import (
+	"github.com/deltatree/showcase/components"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// beginBlend switches raylib to the blend mode. Every call must be paired
+// with rl.EndBlendMode.
+//
+// Screen blending needs premultiplied colors (see premultiply): with
+// factors (1, 1 - src) the result is src + dst - src·dst.
+func beginBlend(mode components.BlendMode) {
+	switch mode {
+	case components.BlendAdditive:
+		rl.BeginBlendMode(rl.BlendAdditive)
+	case components.BlendMultiply:
+		rl.BeginBlendMode(rl.BlendMultiplied)
+	case components.BlendScreen:
+		rl.SetBlendFactors(rl.One, rl.OneMinusSrcColor, rl.FuncAdd)
+		rl.BeginBlendMode(rl.BlendCustom)
+	default:
+		rl.BeginBlendMode(rl.BlendAlpha)
+	}
+}
+
+// premultiply scales a color by its alpha and makes it opaque, for blend
+// modes whose factors ignore the source alpha.
+func premultiply(r, g, b, a uint8) (uint8, uint8, uint8, uint8) {
+	return uint8(uint16(r) * uint16(a) / 255),
+		uint8(uint16(g) * uint16(a) / 255),
+		uint8(uint16(b) * uint16(a) / 255),
+		255
+}
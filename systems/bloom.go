@@ -0,0 +1,290 @@
+package systems
+
+import (
+	"image/color"
+
+	"github.com/deltatree/showcase/premium"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Bloom shaders use raylib's default vertex shader, which provides
+// fragTexCoord and fragColor.
+
+// bloomThresholdShader keeps the bright parts of the scene.
+const bloomThresholdShader = `#version 330
+in vec2 fragTexCoord;
+in vec4 fragColor;
+uniform sampler2D texture0;
+uniform float threshold;
+out vec4 finalColor;
+
+void main() {
+	vec3 c = texture(texture0, fragTexCoord).rgb;
+	float luma = max(c.r, max(c.g, c.b));
+	float k = max(luma - threshold, 0.0) / max(1.0 - threshold, 0.0001);
+	finalColor = vec4(c * k, 1.0);
+}
+`
+
+// bloomBlurShader is one direction of a separable 9-tap Gaussian blur.
+// direction is the texel step scaled by the blur radius.
+const bloomBlurShader = `#version 330
+in vec2 fragTexCoord;
+in vec4 fragColor;
+uniform sampler2D texture0;
+uniform vec2 direction;
+out vec4 finalColor;
+
+const float weights[5] = float[](0.2270270, 0.1945946, 0.1216216, 0.0540540, 0.0162162);
+
+void main() {
+	vec3 sum = texture(texture0, fragTexCoord).rgb * weights[0];
+	for (int i = 1; i < 5; i++) {
+		vec2 offset = direction * float(i);
+		sum += texture(texture0, fragTexCoord + offset).rgb * weights[i];
+		sum += texture(texture0, fragTexCoord - offset).rgb * weights[i];
+	}
+	finalColor = vec4(sum, 1.0);
+}
+`
+
+// bloomCompositeShader adds the blurred highlights onto the scene.
+const bloomCompositeShader = `#version 330
+in vec2 fragTexCoord;
+in vec4 fragColor;
+uniform sampler2D texture0;
+uniform sampler2D bloom;
+uniform float intensity;
+out vec4 finalColor;
+
+void main() {
+	vec3 scene = texture(texture0, fragTexCoord).rgb;
+	vec3 glow = texture(bloom, fragTexCoord).rgb;
+	finalColor = vec4(scene + glow * intensity, 1.0);
+}
+`
+
+// BloomRenderer is a GPU bloom post-process. The scene is drawn into an
+// offscreen render texture; its bright parts are extracted by a threshold
+// shader into a downsampled buffer, blurred horizontally and vertically
+// with a Gaussian shader and added back onto the scene by a composite
+// shader.
+//
+// Unlike GlowRenderer, the cost does not grow with the particle count: the
+// particles are drawn once and the blur runs on a few full-screen quads.
+// The blur radius and downsample factor follow the quality level.
+//
+// GPU resources are created by Load once the window exists and released
+// by Unload.
+type BloomRenderer struct {
+	enabled    bool
+	radius     float32
+	downsample int
+	threshold  float32
+	intensity  float32
+
+	width, height int32
+	loaded        bool
+
+	scene, ping, pong rl.RenderTexture2D
+
+	thresholdShader, blurShader, compositeShader rl.Shader
+	thresholdLoc, directionLoc                   int32
+	bloomLoc, intensityLoc                       int32
+}
+
+// NewBloomRenderer creates a bloom renderer for a screen of the given size.
+func NewBloomRenderer(width, height int32) *BloomRenderer {
+	return &BloomRenderer{
+		radius:     1.5,
+		downsample: 4,
+		threshold:  0.35,
+		intensity:  1.2,
+		width:      width,
+		height:     height,
+	}
+}
+
+// Load compiles the shaders and creates the render textures. It must be
+// called after the window has been created. If the shaders cannot be
+// compiled, bloom stays inactive.
+func (r *BloomRenderer) Load() {
+	if r.loaded {
+		return
+	}
+	r.thresholdShader = rl.LoadShaderFromMemory("", bloomThresholdShader)
+	r.blurShader = rl.LoadShaderFromMemory("", bloomBlurShader)
+	r.compositeShader = rl.LoadShaderFromMemory("", bloomCompositeShader)
+	if !rl.IsShaderValid(r.thresholdShader) || !rl.IsShaderValid(r.blurShader) || !rl.IsShaderValid(r.compositeShader) {
+		r.unloadShaders()
+		return
+	}
+
+	r.thresholdLoc = rl.GetShaderLocation(r.thresholdShader, "threshold")
+	r.directionLoc = rl.GetShaderLocation(r.blurShader, "direction")
+	r.bloomLoc = rl.GetShaderLocation(r.compositeShader, "bloom")
+	r.intensityLoc = rl.GetShaderLocation(r.compositeShader, "intensity")
+
+	r.loadTargets()
+	r.loaded = true
+}
+
+// loadTargets creates the scene texture and the two downsampled blur
+// buffers.
+func (r *BloomRenderer) loadTargets() {
+	r.scene = rl.LoadRenderTexture(r.width, r.height)
+	w, h := r.bufferSize()
+	r.ping = rl.LoadRenderTexture(w, h)
+	r.pong = rl.LoadRenderTexture(w, h)
+	rl.SetTextureFilter(r.ping.Texture, rl.FilterBilinear)
+	rl.SetTextureFilter(r.pong.Texture, rl.FilterBilinear)
+}
+
+func (r *BloomRenderer) unloadTargets() {
+	rl.UnloadRenderTexture(r.scene)
+	rl.UnloadRenderTexture(r.ping)
+	rl.UnloadRenderTexture(r.pong)
+}
+
+func (r *BloomRenderer) unloadShaders() {
+	rl.UnloadShader(r.thresholdShader)
+	rl.UnloadShader(r.blurShader)
+	rl.UnloadShader(r.compositeShader)
+}
+
+// Unload releases the GPU resources.
+func (r *BloomRenderer) Unload() {
+	if !r.loaded {
+		return
+	}
+	r.unloadTargets()
+	r.unloadShaders()
+	r.loaded = false
+}
+
+// bufferSize returns the size of the downsampled blur buffers.
+func (r *BloomRenderer) bufferSize() (int32, int32) {
+	d := int32(max(r.downsample, 1))
+	return max(r.width/d, 1), max(r.height/d, 1)
+}
+
+// Active reports whether the next frame is post-processed.
+func (r *BloomRenderer) Active() bool {
+	return r.enabled && r.loaded
+}
+
+// Begin redirects drawing into the scene texture and clears it with
+// background. It reports whether bloom is active; if not, drawing goes to
+// the screen as usual and End must not be called.
+func (r *BloomRenderer) Begin(background color.RGBA) bool {
+	if !r.Active() {
+		return false
+	}
+	rl.BeginTextureMode(r.scene)
+	rl.ClearBackground(background)
+	return true
+}
+
+// End finishes the scene and draws it to the screen with bloom applied.
+func (r *BloomRenderer) End() {
+	rl.EndTextureMode()
+	r.Composite(r.scene)
+}
+
+// Composite draws a scene rendered elsewhere, e.g. accumulated by a
+// persistent background, to the screen with bloom applied. Bloom must be
+// Active.
+func (r *BloomRenderer) Composite(scene rl.RenderTexture2D) {
+	r.blur(scene)
+	r.draw(scene)
+}
+
+// blur extracts the bright parts of scene into the ping buffer and blurs
+// them. Drawing goes to the screen afterwards.
+func (r *BloomRenderer) blur(scene rl.RenderTexture2D) {
+	w, h := r.bufferSize()
+
+	// Bright pass, downsampled
+	rl.SetShaderValue(r.thresholdShader, r.thresholdLoc, []float32{r.threshold}, rl.ShaderUniformFloat)
+	r.pass(r.thresholdShader, scene, r.ping, w, h)
+
+	// Separable Gaussian blur: horizontal into pong, vertical back into ping
+	rl.SetShaderValue(r.blurShader, r.directionLoc, []float32{r.radius / float32(w), 0}, rl.ShaderUniformVec2)
+	r.pass(r.blurShader, r.ping, r.pong, w, h)
+	rl.SetShaderValue(r.blurShader, r.directionLoc, []float32{0, r.radius / float32(h)}, rl.ShaderUniformVec2)
+	r.pass(r.blurShader, r.pong, r.ping, w, h)
+}
+
+// draw adds the blurred highlights onto scene and draws the result to the
+// current target.
+func (r *BloomRenderer) draw(scene rl.RenderTexture2D) {
+	rl.BeginShaderMode(r.compositeShader)
+	rl.SetShaderValueTexture(r.compositeShader, r.bloomLoc, r.ping.Texture)
+	rl.SetShaderValue(r.compositeShader, r.intensityLoc, []float32{r.intensity}, rl.ShaderUniformFloat)
+	drawTarget(scene, float32(r.width), float32(r.height))
+	rl.EndShaderMode()
+}
+
+// pass draws src into dst (sized w×h) through shader.
+func (r *BloomRenderer) pass(shader rl.Shader, src, dst rl.RenderTexture2D, w, h int32) {
+	rl.BeginTextureMode(dst)
+	rl.ClearBackground(rl.Black)
+	rl.BeginShaderMode(shader)
+	drawTarget(src, float32(w), float32(h))
+	rl.EndShaderMode()
+	rl.EndTextureMode()
+}
+
+// drawTarget draws a render texture stretched to w×h at the origin.
+// Render textures are stored upside down, hence the negative height.
+func drawTarget(target rl.RenderTexture2D, w, h float32) {
+	src := rl.NewRectangle(0, 0, float32(target.Texture.Width), -float32(target.Texture.Height))
+	rl.DrawTexturePro(target.Texture, src, rl.NewRectangle(0, 0, w, h), rl.Vector2{}, 0, rl.White)
+}
+
+// Resize sets the screen size, recreating the render textures if they
+// are loaded.
+func (r *BloomRenderer) Resize(width, height int32) {
+	if width == r.width && height == r.height {
+		return
+	}
+	r.width, r.height = width, height
+	if r.loaded {
+		r.unloadTargets()
+		r.loadTargets()
+	}
+}
+
+// SetEnabled enables or disables bloom.
+func (r *BloomRenderer) SetEnabled(enabled bool) {
+	r.enabled = enabled
+}
+
+// SetThreshold sets the brightness (0.0 to 1.0) above which colors bloom.
+func (r *BloomRenderer) SetThreshold(threshold float32) {
+	r.threshold = threshold
+}
+
+// SetIntensity sets how strongly the blurred highlights are added.
+func (r *BloomRenderer) SetIntensity(intensity float32) {
+	r.intensity = intensity
+}
+
+// ApplyQuality applies quality settings to bloom. A changed downsample
+// factor recreates the blur buffers.
+func (r *BloomRenderer) ApplyQuality(q premium.QualitySettings) {
+	r.enabled = q.Bloom
+	r.radius = q.BloomRadius
+	if q.BloomDownsample > 0 && q.BloomDownsample != r.downsample {
+		r.downsample = q.BloomDownsample
+		if r.loaded {
+			r.unloadTargets()
+			r.loadTargets()
+		}
+	}
+}
+
+// IsEnabled returns whether bloom is enabled.
+func (r *BloomRenderer) IsEnabled() bool {
+	return r.enabled
+}
@@ -0,0 +1,62 @@
+package systems
+
+import (
+	"math"
+
+	"github.com/andygeiss/ecs"
+	"github.com/deltatree/showcase/components"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// CameraSystem moves each Camera towards the entity named by its Follow
+// field. With Smoothing above 0 the camera closes the distance
+// exponentially, so it glides after fast targets instead of jittering
+// with them. Cameras whose target no longer exists stay where they are.
+//
+// The system runs after PhysicsSystem, so the camera follows the position
+// drawn in the same frame.
+type cameraSystem struct{}
+
+// NewCameraSystem creates a new camera follow system.
+func NewCameraSystem() ecs.System {
+	return &cameraSystem{}
+}
+
+func (s *cameraSystem) Setup() {}
+
+func (s *cameraSystem) Process(em ecs.EntityManager) (state int) {
+	s.update(em, rl.GetFrameTime())
+	return ecs.StateEngineContinue
+}
+
+// update moves the cameras dt seconds towards their targets.
+func (s *cameraSystem) update(em ecs.EntityManager, dt float32) {
+	for _, e := range query(em, components.MaskCamera) {
+		cam := e.Get(components.MaskCamera).(*components.Camera)
+		if cam.Follow == "" {
+			continue
+		}
+		target := em.Get(cam.Follow)
+		if target == nil || target.Masked&components.MaskPosition == 0 {
+			continue
+		}
+		pos := target.Get(components.MaskPosition).(*components.Position)
+
+		t := float32(1)
+		if cam.Smoothing > 0 {
+			t = 1 - float32(math.Exp(float64(-cam.Smoothing*dt)))
+		}
+		cam.X += (pos.X - cam.X) * t
+		cam.Y += (pos.Y - cam.Y) * t
+	}
+}
+
+func (s *cameraSystem) Teardown() {}
+
+// findCamera returns the first Camera in em, or nil.
+func findCamera(em ecs.EntityManager) *components.Camera {
+	if cams := query(em, components.MaskCamera); len(cams) > 0 {
+		return cams[0].Get(components.MaskCamera).(*components.Camera)
+	}
+	return nil
+}
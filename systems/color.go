@@ -13,37 +13,98 @@ import (
 //   - Fire effects: orange → red → transparent
 //   - Sparks: large bright → small dim
 //   - Trails: visible → invisible
-type colorSystem struct{}
+//
+// Entities with a Gradient component are colored from its stops instead,
+// e.g. fire going white → yellow → orange → red → smoke → transparent.
+// Colors and gradients are interpolated in their ColorSpace (sRGB bytes,
+// linear RGB, HSV or OKLab). SizeOverLife and AlphaOverLife replace the
+// linear size and alpha interpolation with curves; with a Gradient, the
+// alpha curve scales the gradient's alpha.
+//
+// If a ParticleStore is attached with UseStore, its particles are
+// interpolated as well. With a WorkerPool attached via SetParallel the
+// particles are processed in parallel chunks.
+type colorSystem struct {
+	store   *ParticleStore
+	workers *WorkerPool
+}
 
 // NewColorSystem creates a new color interpolation system.
-func NewColorSystem() ecs.System {
+func NewColorSystem() *colorSystem {
 	return &colorSystem{}
 }
 
 func (s *colorSystem) Setup() {}
 
 func (s *colorSystem) Process(em ecs.EntityManager) (state int) {
-	entities := em.FilterByMask(components.MaskColor | components.MaskLifetime)
+	entities := query(em, components.MaskColor|components.MaskLifetime)
+	s.workers.For(len(entities), func(lo, hi int) {
+		for _, e := range entities[lo:hi] {
+			col := e.Get(components.MaskColor).(*components.Color)
+			life := e.Get(components.MaskLifetime).(*components.Lifetime)
 
-	for _, e := range entities {
-		col := e.Get(components.MaskColor).(*components.Color)
-		life := e.Get(components.MaskLifetime).(*components.Lifetime)
+			t := life.Progress()
 
-		t := life.Progress()
+			// Multi-stop gradients override the linear start/end
+			// interpolation
+			alpha := col.StartA
+			switch {
+			case e.Masked&components.MaskGradient != 0:
+				grad := e.Get(components.MaskGradient).(*components.Gradient)
+				col.R, col.G, col.B, col.A = grad.Evaluate(t)
+				alpha = col.A
+			case col.Space != components.SpaceSRGB:
+				col.R, col.G, col.B, col.A = col.Space.Mix(
+					col.StartR, col.StartG, col.StartB, col.StartA,
+					col.EndR, col.EndG, col.EndB, col.EndA, t,
+				)
+			default:
+				col.R = lerp(col.StartR, col.EndR, t)
+				col.G = lerp(col.StartG, col.EndG, t)
+				col.B = lerp(col.StartB, col.EndB, t)
+				col.A = lerp(col.StartA, col.EndA, t)
+			}
 
-		col.R = lerp(col.StartR, col.EndR, t)
-		col.G = lerp(col.StartG, col.EndG, t)
-		col.B = lerp(col.StartB, col.EndB, t)
-		col.A = lerp(col.StartA, col.EndA, t)
-	}
+			// An alpha curve replaces the linear alpha interpolation and
+			// scales a gradient's alpha
+			if e.Masked&components.MaskAlphaOverLife != 0 {
+				aol := e.Get(components.MaskAlphaOverLife).(*components.AlphaOverLife)
+				a := float32(alpha) * aol.Curve.At(t)
+				col.A = uint8(min(max(a, 0), 255))
+			}
+		}
+	})
+
+	sizeEntities := query(em, components.MaskSize|components.MaskLifetime)
+	s.workers.For(len(sizeEntities), func(lo, hi int) {
+		for _, e := range sizeEntities[lo:hi] {
+			size := e.Get(components.MaskSize).(*components.Size)
+			life := e.Get(components.MaskLifetime).(*components.Lifetime)
 
-	sizeEntities := em.FilterByMask(components.MaskSize | components.MaskLifetime)
-	for _, e := range sizeEntities {
-		size := e.Get(components.MaskSize).(*components.Size)
-		life := e.Get(components.MaskLifetime).(*components.Lifetime)
+			t := life.Progress()
+			if e.Masked&components.MaskSizeOverLife != 0 {
+				sol := e.Get(components.MaskSizeOverLife).(*components.SizeOverLife)
+				size.Radius = size.StartSize * sol.Curve.At(t)
+				continue
+			}
+			size.Radius = lerpF(size.StartSize, size.EndSize, t)
+		}
+	})
 
-		t := life.Progress()
-		size.Radius = lerpF(size.StartSize, size.EndSize, t)
+	if st := s.store; st != nil {
+		s.workers.For(st.Len(), func(lo, hi int) {
+			for i := lo; i < hi; i++ {
+				t := st.Progress(i)
+				start, end := st.StartColor[i], st.EndColor[i]
+				st.Color[i] = RGBA{
+					lerp(start[0], end[0], t),
+					lerp(start[1], end[1], t),
+					lerp(start[2], end[2], t),
+					lerp(start[3], end[3], t),
+				}
+				st.Radius[i] = lerpF(st.StartSize[i], st.EndSize[i], t)
+			}
+		})
 	}
 
 	return ecs.StateEngineContinue
@@ -57,4 +118,14 @@ func lerpF(a, b, t float32) float32 {
 	return a + (b-a)*t
 }
 
+// UseStore makes the system interpolate the particles of store.
+func (s *colorSystem) UseStore(store *ParticleStore) {
+	s.store = store
+}
+
+// SetParallel spreads the work over pool (nil runs sequentially).
+func (s *colorSystem) SetParallel(pool *WorkerPool) {
+	s.workers = pool
+}
+
 func (s *colorSystem) Teardown() {}
@@ -0,0 +1,211 @@
+package systems
+
+import (
+	"sync"
+
+	"github.com/andygeiss/ecs"
+)
+
+type commandKind uint8
+
+const (
+	cmdAdd commandKind = iota
+	cmdRemove
+	cmdAddComponent
+	cmdRemoveComponent
+	cmdRefresh
+)
+
+type command struct {
+	kind      commandKind
+	entity    *ecs.Entity
+	component ecs.Component
+	mask      uint64
+}
+
+// CommandBuffer records structural changes to the world — adding and
+// removing entities and components — and applies them later in one batch.
+//
+// Systems record into the buffer while they iterate entity lists, so the
+// lists never change under them, and NewCommandFlushSystem applies the
+// recorded commands at a fixed point in the frame. Recording is safe from
+// multiple goroutines.
+//
+// Commands are applied in the order they were recorded. Removed particle
+// entities are returned to the pool set with SetPool.
+type CommandBuffer struct {
+	mu       sync.Mutex
+	commands []command
+	spare    []command
+	pool     *ParticlePool
+}
+
+// NewCommandBuffer creates an empty command buffer.
+func NewCommandBuffer() *CommandBuffer {
+	return &CommandBuffer{}
+}
+
+// SetPool sets the pool removed particle entities are recycled into.
+func (b *CommandBuffer) SetPool(pool *ParticlePool) {
+	b.pool = pool
+}
+
+// Add records adding entities to the world.
+func (b *CommandBuffer) Add(entities ...*ecs.Entity) {
+	b.mu.Lock()
+	for _, e := range entities {
+		b.commands = append(b.commands, command{kind: cmdAdd, entity: e})
+	}
+	b.mu.Unlock()
+}
+
+// Remove records removing an entity from the world.
+func (b *CommandBuffer) Remove(e *ecs.Entity) {
+	b.record(command{kind: cmdRemove, entity: e})
+}
+
+// AddComponent records adding a component to an entity.
+func (b *CommandBuffer) AddComponent(e *ecs.Entity, c ecs.Component) {
+	b.record(command{kind: cmdAddComponent, entity: e, component: c})
+}
+
+// RemoveComponent records removing the component with the given mask from
+// an entity.
+func (b *CommandBuffer) RemoveComponent(e *ecs.Entity, mask uint64) {
+	b.record(command{kind: cmdRemoveComponent, entity: e, mask: mask})
+}
+
+// Len returns the number of pending commands.
+func (b *CommandBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.commands)
+}
+
+func (b *CommandBuffer) record(c command) {
+	b.mu.Lock()
+	b.commands = append(b.commands, c)
+	b.mu.Unlock()
+}
+
+// Flush applies all pending commands to em. Commands recorded while
+// flushing are kept for the next flush.
+func (b *CommandBuffer) Flush(em ecs.EntityManager) {
+	b.mu.Lock()
+	cmds := b.commands
+	b.commands = b.spare[:0]
+	b.mu.Unlock()
+
+	for _, c := range cmds {
+		switch c.kind {
+		case cmdAdd:
+			em.Add(c.entity)
+		case cmdRemove:
+			if removeEntity(em, c.entity) && b.pool != nil {
+				b.pool.Release(c.entity)
+			}
+		case cmdAddComponent:
+			c.entity.Add(c.component)
+			refreshEntity(em, c.entity)
+		case cmdRemoveComponent:
+			c.entity.Remove(c.mask)
+			refreshEntity(em, c.entity)
+		case cmdRefresh:
+			refreshEntity(em, c.entity)
+		}
+	}
+
+	clear(cmds)
+	b.mu.Lock()
+	b.spare = cmds[:0]
+	b.mu.Unlock()
+}
+
+// Deferred returns a view of em whose Add and Remove are recorded into the
+// buffer instead of being applied immediately. Reads go straight to em,
+// so entities added through the view are not visible before the flush.
+//
+// Presets can be applied through the view while systems are running:
+//
+//	preset.Apply(commands.Deferred(em), cfg)
+func (b *CommandBuffer) Deferred(em ecs.EntityManager) ecs.EntityManager {
+	return &deferredManager{EntityManager: em, buffer: b}
+}
+
+// deferredManager is the entity manager view returned by Deferred.
+type deferredManager struct {
+	ecs.EntityManager
+	buffer *CommandBuffer
+}
+
+func (d *deferredManager) Add(entities ...*ecs.Entity) { d.buffer.Add(entities...) }
+
+func (d *deferredManager) Remove(e *ecs.Entity) { d.buffer.Remove(e) }
+
+// Refresh records re-indexing an entity whose components were changed
+// directly, so a QueryCache behind the view stays consistent.
+func (d *deferredManager) Refresh(e *ecs.Entity) {
+	d.buffer.record(command{kind: cmdRefresh, entity: e})
+}
+
+// NewCommandFlushSystem creates a system that flushes buffer each frame.
+// Register it where structural changes should become visible, e.g. after
+// LifetimeSystem and before the systems that draw.
+func NewCommandFlushSystem(buffer *CommandBuffer) ecs.System {
+	return &commandFlushSystem{buffer: buffer}
+}
+
+type commandFlushSystem struct {
+	buffer *CommandBuffer
+}
+
+func (s *commandFlushSystem) Setup() {}
+
+func (s *commandFlushSystem) Process(em ecs.EntityManager) (state int) {
+	s.buffer.Flush(em)
+	return ecs.StateEngineContinue
+}
+
+func (s *commandFlushSystem) Teardown() {}
+
+// removeEntity removes e from em and reports whether e itself was removed.
+// A QueryCache removes by identity, so entities it does not hold are left
+// alone. Other entity managers remove by Id, which is unique for
+// particles (see components.NewParticleID); the pool ignores particles
+// released twice. Only entities without an Id are looked up, as removing
+// one could hit another entity without an Id.
+func removeEntity(em ecs.EntityManager, e *ecs.Entity) bool {
+	if c, ok := em.(*QueryCache); ok {
+		if _, held := c.all.index[e]; !held {
+			return false
+		}
+		c.Remove(e)
+		return true
+	}
+	if e.Id != "" {
+		em.Remove(e)
+		return true
+	}
+	if !holds(em, e) {
+		return false
+	}
+	em.Remove(e)
+	return !holds(em, e)
+}
+
+// holds reports whether e itself is one of em's entities.
+func holds(em ecs.EntityManager, e *ecs.Entity) bool {
+	for _, other := range em.Entities() {
+		if other == e {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshEntity re-indexes e if em caches queries.
+func refreshEntity(em ecs.EntityManager, e *ecs.Entity) {
+	if c, ok := em.(*QueryCache); ok {
+		c.Refresh(e)
+	}
+}
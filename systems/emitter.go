@@ -1,13 +1,15 @@
 package systems
 
 import (
-	"fmt"
+	"math"
 	"math/rand"
 	"time"
 
 	"github.com/andygeiss/ecs"
 	"github.com/deltatree/showcase/components"
+	"github.com/deltatree/showcase/curves"
 	"github.com/deltatree/showcase/premium"
+	"github.com/deltatree/showcase/shapes"
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
@@ -18,16 +20,43 @@ import (
 // Spawn patterns:
 //   - "random": particles spawn at random screen positions
 //   - "center": particles spawn near screen center
+//   - "edges": particles spawn along the screen border
 //   - "emitter": particles spawn at emitter entity positions
+//   - "mask": particles spawn on the points of a spawn mask (SetSpawnMask)
+//
+// The spawn rate is either fixed (SetSpawnRate) or driven by a RateCurve
+// over the emitter's age (SetSpawnRateCurve). Independently of the rate,
+// distance-based emission (SetDistanceEmission) spawns particles along the
+// path travelled by each emitter entity, so a moving emitter leaves evenly
+// spaced trails regardless of its speed.
+//
+// When the quality level has a TrailLength, spawned particles get a Trail
+// of that length for TrailRenderer.
 type emitterSystem struct {
 	spawnRate    int
-	spawnTimer   float32
+	spawnBudget  float32 // fractional particles owed by the rate curve
 	maxParticles int
 	width        float32
 	height       float32
 	rng          *rand.Rand
-	idCounter    int64
+	pool         *ParticlePool
+	store        *ParticleStore
+	gradient     *components.Gradient
+	sizeCurve    *components.SizeOverLife
+	alphaCurve   *components.AlphaOverLife
+	speedCurve   *components.SpeedOverLife
+	sprite       *components.Sprite
+	spin         float32
+	colorSpace   components.ColorSpace
 	quality      premium.QualitySettings
+	age          float32
+	rateCurve    RateCurve
+	// Distance-based emission state
+	particlesPerPixel float32
+	trails            map[string]*emitterTrail
+	// Spawn mask state for the "mask" pattern
+	mask            shapes.PointSet
+	maskSampleColor bool
 	// Configurable spawn parameters set by presets
 	StartColorR, StartColorG, StartColorB, StartColorA uint8
 	EndColorR, EndColorG, EndColorB, EndColorA         uint8
@@ -60,17 +89,34 @@ func NewEmitterSystem(spawnRate, maxParticles int, width, height float32) *emitt
 		MinVel:       -50,
 		MaxVel:       50,
 		SpawnPattern: "random",
+		trails:       make(map[string]*emitterTrail),
+		pool:         NewParticlePool(),
 	}
 }
 
+// emitterTrail remembers where an emitter entity was last frame and how
+// much travelled distance has not yet been converted into particles.
+type emitterTrail struct {
+	x, y  float32
+	carry float32
+}
+
 func (s *emitterSystem) Setup() {}
 
 func (s *emitterSystem) Process(em ecs.EntityManager) (state int) {
-	dt := rl.GetFrameTime()
-	s.spawnTimer += dt
+	s.update(em, rl.GetFrameTime())
+	return ecs.StateEngineContinue
+}
+
+// update advances the emitter by dt seconds and spawns the particles owed
+// by the rate curve and by distance-based emission.
+func (s *emitterSystem) update(em ecs.EntityManager, dt float32) {
+	s.age += dt
 
-	particles := em.FilterByMask(components.MaskParticle)
-	currentCount := len(particles)
+	currentCount := countParticles(em)
+	if s.store != nil {
+		currentCount += s.store.Len()
+	}
 
 	// Use quality-based max particles
 	maxAllowed := s.quality.MaxParticles
@@ -78,24 +124,97 @@ func (s *emitterSystem) Process(em ecs.EntityManager) (state int) {
 		maxAllowed = s.maxParticles
 	}
 
-	if s.spawnRate <= 0 {
-		return ecs.StateEngineContinue
+	if rate := s.currentRate(); rate > 0 {
+		s.spawnBudget += rate * dt
+		for s.spawnBudget >= 1 && currentCount < maxAllowed {
+			s.spawnBudget--
+			s.spawnParticle(em)
+			currentCount++
+		}
+	} else {
+		s.spawnBudget = 0
 	}
-	spawnInterval := 1.0 / float32(s.spawnRate)
 
-	for s.spawnTimer >= spawnInterval && currentCount < maxAllowed {
-		s.spawnTimer -= spawnInterval
-		s.spawnParticle(em)
-		currentCount++
+	if s.particlesPerPixel > 0 {
+		s.emitAlongPaths(em, currentCount, maxAllowed)
 	}
+}
 
-	return ecs.StateEngineContinue
+// currentRate returns the spawn rate in particles per second for the
+// current emitter age.
+func (s *emitterSystem) currentRate() float32 {
+	if s.rateCurve != nil {
+		return s.rateCurve.Rate(s.age)
+	}
+	return float32(s.spawnRate)
+}
+
+// emitAlongPaths spawns particles evenly spaced along the segment each
+// emitter entity travelled since the previous frame.
+func (s *emitterSystem) emitAlongPaths(em ecs.EntityManager, currentCount, maxAllowed int) {
+	emitters := query(em, components.MaskEmitter|components.MaskPosition)
+	spacing := 1 / s.particlesPerPixel
+
+	seen := make(map[string]bool, len(emitters))
+	for _, e := range emitters {
+		pos := e.Get(components.MaskPosition).(*components.Position)
+		seen[e.Id] = true
+
+		trail, ok := s.trails[e.Id]
+		if !ok {
+			s.trails[e.Id] = &emitterTrail{x: pos.X, y: pos.Y}
+			continue
+		}
+
+		dx := pos.X - trail.x
+		dy := pos.Y - trail.y
+		dist := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+		if dist > 0 {
+			// Walk the segment in fixed steps, carrying the remainder
+			// into the next frame so spacing stays even.
+			d := spacing - trail.carry
+			for d <= dist && currentCount < maxAllowed {
+				t := d / dist
+				s.spawnParticleAt(em, trail.x+dx*t, trail.y+dy*t)
+				currentCount++
+				d += spacing
+			}
+			trail.carry = dist - (d - spacing)
+			if trail.carry >= spacing {
+				trail.carry = 0
+			}
+		}
+		trail.x, trail.y = pos.X, pos.Y
+	}
+
+	for id := range s.trails {
+		if !seen[id] {
+			delete(s.trails, id)
+		}
+	}
 }
 
 func (s *emitterSystem) spawnParticle(em ecs.EntityManager) {
 	var x, y float32
 
 	switch s.SpawnPattern {
+	case "emitter":
+		emitters := query(em, components.MaskEmitter|components.MaskPosition)
+		if len(emitters) == 0 {
+			return
+		}
+		pos := emitters[s.rng.Intn(len(emitters))].Get(components.MaskPosition).(*components.Position)
+		x, y = pos.X, pos.Y
+	case "mask":
+		if len(s.mask) == 0 {
+			return
+		}
+		p := s.mask[s.rng.Intn(len(s.mask))]
+		if s.maskSampleColor {
+			s.spawnParticleColored(em, p.X, p.Y, p.R, p.G, p.B, p.A)
+			return
+		}
+		x, y = p.X, p.Y
 	case "center":
 		x = s.width/2 + (s.rng.Float32()-0.5)*100
 		y = s.height/2 + (s.rng.Float32()-0.5)*100
@@ -120,26 +239,69 @@ func (s *emitterSystem) spawnParticle(em ecs.EntityManager) {
 		y = s.rng.Float32() * s.height
 	}
 
+	s.spawnParticleAt(em, x, y)
+}
+
+// spawnParticleAt creates a particle at (x, y) using the configured
+// color, size, velocity and lifetime ranges.
+func (s *emitterSystem) spawnParticleAt(em ecs.EntityManager, x, y float32) {
+	s.spawnParticleColored(em, x, y, s.StartColorR, s.StartColorG, s.StartColorB, s.StartColorA)
+}
+
+// spawnParticleColored creates a particle at (x, y) that starts with the
+// given color and fades towards the configured end color.
+func (s *emitterSystem) spawnParticleColored(em ecs.EntityManager, x, y float32, r, g, b, a uint8) {
 	vx := s.MinVel + s.rng.Float32()*(s.MaxVel-s.MinVel)
 	vy := s.MinVel + s.rng.Float32()*(s.MaxVel-s.MinVel)
 	size := s.MinSize + s.rng.Float32()*(s.MaxSize-s.MinSize)
 	ttl := s.MinTTL + s.rng.Float32()*(s.MaxTTL-s.MinTTL)
 
-	s.idCounter++
-	id := fmt.Sprintf("p-%d", s.idCounter)
+	if st := s.store; st != nil {
+		i := st.Spawn()
+		st.X[i], st.Y[i] = x, y
+		st.VX[i], st.VY[i] = vx, vy
+		st.TTL[i] = ttl
+		st.Radius[i], st.StartSize[i], st.EndSize[i] = size, size, size*0.3
+		st.StartColor[i] = RGBA{r, g, b, a}
+		st.Color[i] = st.StartColor[i]
+		st.EndColor[i] = RGBA{s.EndColorR, s.EndColorG, s.EndColorB, s.EndColorA}
+		return
+	}
 
-	em.Add(ecs.NewEntity(id, []ecs.Component{
-		components.NewPosition().With(x, y),
-		components.NewVelocity().With(vx, vy),
-		components.NewAcceleration(),
-		components.NewColor().WithGradient(
-			s.StartColorR, s.StartColorG, s.StartColorB, s.StartColorA,
-			s.EndColorR, s.EndColorG, s.EndColorB, s.EndColorA,
-		),
-		components.NewLifetime().WithTTL(ttl),
-		components.NewSize().WithRadius(size).WithEndSize(size * 0.3),
-		components.NewParticle(),
-	}))
+	p := s.pool.Acquire()
+	p.Position.With(x, y)
+	p.Velocity.With(vx, vy)
+	p.Color.WithGradient(
+		r, g, b, a,
+		s.EndColorR, s.EndColorG, s.EndColorB, s.EndColorA,
+	)
+	p.Color.Space = s.colorSpace
+	p.Lifetime.WithTTL(ttl)
+	p.Size.WithRadius(size).WithEndSize(size * 0.3)
+	if s.gradient != nil {
+		p.Entity.Add(s.gradient)
+	}
+	if s.sizeCurve != nil {
+		p.Entity.Add(s.sizeCurve)
+	}
+	if s.alphaCurve != nil {
+		p.Entity.Add(s.alphaCurve)
+	}
+	if s.speedCurve != nil {
+		p.Entity.Add(s.speedCurve)
+	}
+	if s.sprite != nil {
+		p.Entity.Add(s.sprite)
+		if s.spin > 0 {
+			rot, av := s.pool.AcquireSpin(s.rng.Float32()*360, (s.rng.Float32()*2-1)*s.spin)
+			p.Entity.Add(rot)
+			p.Entity.Add(av)
+		}
+	}
+	if n := s.quality.TrailLength; n > 0 {
+		p.Entity.Add(s.pool.AcquireTrail(n))
+	}
+	em.Add(p.Entity)
 }
 
 func (s *emitterSystem) Teardown() {}
@@ -150,14 +312,117 @@ func (s *emitterSystem) SetColors(sr, sg, sb, sa, er, eg, eb, ea uint8) {
 	s.EndColorR, s.EndColorG, s.EndColorB, s.EndColorA = er, eg, eb, ea
 }
 
+// SetPool replaces the particle pool used for spawning. Share the pool with
+// LifetimeSystem.SetPool so expired particles are recycled.
+func (s *emitterSystem) SetPool(pool *ParticlePool) {
+	s.pool = pool
+}
+
+// UseStore makes the emitter spawn into store instead of creating
+// entities. Particles already in the store count towards the limit.
+func (s *emitterSystem) UseStore(store *ParticleStore) {
+	s.store = store
+}
+
+// GetPool returns the particle pool used for spawning.
+func (s *emitterSystem) GetPool() *ParticlePool {
+	return s.pool
+}
+
+// SetGradient colors spawned particles along a multi-stop gradient instead
+// of the start/end colors (nil restores them). The gradient is shared by
+// all particles. Particles spawned into a ParticleStore keep using the
+// start/end colors.
+func (s *emitterSystem) SetGradient(gradient *components.Gradient) {
+	s.gradient = gradient
+}
+
+// SetSprite draws spawned particles with sprite (nil draws circles). With
+// spin > 0 each particle starts at a random angle and rotates at a random
+// rate of up to spin degrees per second in either direction. Particles
+// spawned into a ParticleStore are drawn as circles.
+func (s *emitterSystem) SetSprite(sprite *components.Sprite, spin float32) {
+	s.sprite = sprite
+	s.spin = spin
+}
+
+// SetOverLifeCurves animates spawned particles' size, alpha and speed
+// along curves over their lifetime. Pass nil for any property that should
+// keep its default behavior. Particles spawned into a ParticleStore ignore
+// the curves.
+func (s *emitterSystem) SetOverLifeCurves(size, alpha, speed curves.Curve) {
+	s.sizeCurve, s.alphaCurve, s.speedCurve = nil, nil, nil
+	if size != nil {
+		s.sizeCurve = components.NewSizeOverLife(size)
+	}
+	if alpha != nil {
+		s.alphaCurve = components.NewAlphaOverLife(alpha)
+	}
+	if speed != nil {
+		s.speedCurve = components.NewSpeedOverLife(speed)
+	}
+}
+
+// SetColorSpace sets the space spawned particles interpolate their
+// start/end colors in.
+func (s *emitterSystem) SetColorSpace(space components.ColorSpace) {
+	s.colorSpace = space
+}
+
+// SetBounds sets the area particles spawn in, e.g. after the window was
+// resized.
+func (s *emitterSystem) SetBounds(width, height float32) {
+	s.width = width
+	s.height = height
+}
+
 // SetSpawnPattern sets the spawn pattern for particles.
 func (s *emitterSystem) SetSpawnPattern(pattern string) {
 	s.SpawnPattern = pattern
 }
 
-// SetSpawnRate sets the spawn rate.
+// SetSpawnRate sets a fixed spawn rate and clears any rate curve.
 func (s *emitterSystem) SetSpawnRate(rate int) {
 	s.spawnRate = rate
+	s.rateCurve = nil
+}
+
+// SetSpawnRateCurve drives the spawn rate by a curve over emitter age.
+// The emitter age restarts at zero; pass nil to return to the fixed rate.
+func (s *emitterSystem) SetSpawnRateCurve(curve RateCurve) {
+	s.rateCurve = curve
+	s.age = 0
+	s.spawnBudget = 0
+}
+
+// SetDistanceEmission sets how many particles emitter entities spawn per
+// pixel travelled (0 disables distance-based emission).
+func (s *emitterSystem) SetDistanceEmission(particlesPerPixel float32) {
+	if particlesPerPixel < 0 {
+		particlesPerPixel = 0
+	}
+	s.particlesPerPixel = particlesPerPixel
+	clear(s.trails)
+}
+
+// SetSpawnMask sets the points used by the "mask" spawn pattern and
+// switches the emitter to that pattern. With sampleColor enabled each
+// particle starts with the color of its mask point instead of the
+// configured start color.
+//
+// Example revealing a logo:
+//
+//	points, _ := shapes.LoadPNG("logo.png", 3, 128)
+//	emitter.SetSpawnMask(points.Centered(640, 360), true)
+func (s *emitterSystem) SetSpawnMask(points shapes.PointSet, sampleColor bool) {
+	s.mask = points
+	s.maskSampleColor = sampleColor
+	s.SpawnPattern = "mask"
+}
+
+// GetAge returns the time in seconds since the rate curve was set.
+func (s *emitterSystem) GetAge() float32 {
+	return s.age
 }
 
 // SetMaxParticles sets the maximum particle count.
@@ -170,7 +435,7 @@ func (s *emitterSystem) GetMaxParticles() int {
 	return s.maxParticles
 }
 
-// SetQuality sets the quality level for particle limits.
+// SetQuality sets the quality level for particle limits and trail length.
 func (s *emitterSystem) SetQuality(level premium.QualityLevel) {
 	s.quality = premium.GetQualitySettings(level)
 }
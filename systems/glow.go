@@ -3,7 +3,6 @@ package systems
 import (
 	"github.com/deltatree/showcase/components"
 	"github.com/deltatree/showcase/premium"
-	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 // GlowRenderer provides glow/bloom effects for particles.
@@ -29,28 +28,30 @@ func (r *GlowRenderer) RenderGlow(
 	size *components.Size,
 	shakeX, shakeY float32,
 ) {
+	r.draw(&immediate, pos.X+shakeX, pos.Y+shakeY, size.Radius, col.R, col.G, col.B, col.A)
+}
+
+// draw draws the glow layers of a particle at screen position (x, y)
+// with d.
+func (r *GlowRenderer) draw(d particleDrawer, x, y, radius float32, cr, cg, cb, ca uint8) {
 	if !r.enabled || r.palette.GlowIntensity <= 0 {
 		return
 	}
 
-	drawX := int32(pos.X + shakeX)
-	drawY := int32(pos.Y + shakeY)
-
 	// Calculate glow alpha based on particle alpha and palette intensity
-	baseAlpha := float32(col.A) * r.palette.GlowIntensity * 0.25
+	baseAlpha := float32(ca) * r.palette.GlowIntensity * 0.25
+
+	// Blend glow color with particle color for more natural look
+	glowR := (uint16(r.palette.GlowR) + uint16(cr)) / 2
+	glowG := (uint16(r.palette.GlowG) + uint16(cg)) / 2
+	glowB := (uint16(r.palette.GlowB) + uint16(cb)) / 2
 
 	// Draw glow layers (largest first, then smaller)
 	for i := r.passes; i >= 1; i-- {
 		layerAlpha := uint8(baseAlpha / float32(i))
-		layerSize := size.Radius * (1.5 + float32(i)*0.8)
-
-		// Blend glow color with particle color for more natural look
-		glowR := (uint16(r.palette.GlowR) + uint16(col.R)) / 2
-		glowG := (uint16(r.palette.GlowG) + uint16(col.G)) / 2
-		glowB := (uint16(r.palette.GlowB) + uint16(col.B)) / 2
+		layerSize := radius * (1.5 + float32(i)*0.8)
 
-		glowColor := rl.NewColor(uint8(glowR), uint8(glowG), uint8(glowB), layerAlpha)
-		rl.DrawCircle(drawX, drawY, layerSize, glowColor)
+		d.Circle(x, y, layerSize, uint8(glowR), uint8(glowG), uint8(glowB), layerAlpha)
 	}
 }
 
@@ -15,49 +15,82 @@ import (
 // fields enabling complex orbital dynamics.
 //
 // Minimum distance is clamped to prevent infinite forces at close range.
-type gravitySystem struct{}
+//
+// If a ParticleStore is attached with UseStore, its particles are
+// attracted as well. With a WorkerPool attached via SetParallel the
+// particles are processed in parallel chunks.
+type gravitySystem struct {
+	store   *ParticleStore
+	workers *WorkerPool
+}
 
 // NewGravitySystem creates a new gravity system.
 // The gravity calculation uses a fixed scale factor of 500.
-func NewGravitySystem() ecs.System {
+func NewGravitySystem() *gravitySystem {
 	return &gravitySystem{}
 }
 
 func (s *gravitySystem) Setup() {}
 
 func (s *gravitySystem) Process(em ecs.EntityManager) (state int) {
-	attractors := em.FilterByMask(components.MaskPosition | components.MaskMass | components.MaskAttractor)
-	particles := em.FilterByMask(components.MaskPosition | components.MaskAcceleration | components.MaskParticle)
+	attractors := query(em, components.MaskPosition|components.MaskMass|components.MaskAttractor)
+	particles := query(em, components.MaskPosition|components.MaskAcceleration|components.MaskParticle)
 
-	for _, particle := range particles {
-		pPos := particle.Get(components.MaskPosition).(*components.Position)
-		pAcc := particle.Get(components.MaskAcceleration).(*components.Acceleration)
+	s.workers.For(len(particles), func(lo, hi int) {
+		for _, particle := range particles[lo:hi] {
+			pPos := particle.Get(components.MaskPosition).(*components.Position)
+			pAcc := particle.Get(components.MaskAcceleration).(*components.Acceleration)
 
-		pAcc.Reset()
-
-		for _, attractor := range attractors {
-			aPos := attractor.Get(components.MaskPosition).(*components.Position)
-			aMass := attractor.Get(components.MaskMass).(*components.Mass)
+			pAcc.X, pAcc.Y = attraction(attractors, pPos.X, pPos.Y)
+		}
+	})
 
-			if aMass.Value == 0 {
-				continue
+	if st := s.store; st != nil {
+		s.workers.For(st.Len(), func(lo, hi int) {
+			for i := lo; i < hi; i++ {
+				st.AX[i], st.AY[i] = attraction(attractors, st.X[i], st.Y[i])
 			}
+		})
+	}
 
-			dx := aPos.X - pPos.X
-			dy := aPos.Y - pPos.Y
+	return ecs.StateEngineContinue
+}
 
-			dist := float32(math.Sqrt(float64(dx*dx + dy*dy)))
-			if dist < 10 {
-				dist = 10
-			}
+// attraction returns the summed acceleration all attractors exert on a
+// particle at (x, y).
+func attraction(attractors []*ecs.Entity, x, y float32) (ax, ay float32) {
+	for _, attractor := range attractors {
+		aPos := attractor.Get(components.MaskPosition).(*components.Position)
+		aMass := attractor.Get(components.MaskMass).(*components.Mass)
 
-			force := aMass.Value / (dist * dist) * 500
+		if aMass.Value == 0 {
+			continue
+		}
+
+		dx := aPos.X - x
+		dy := aPos.Y - y
 
-			pAcc.Add(dx/dist*force, dy/dist*force)
+		dist := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+		if dist < 10 {
+			dist = 10
 		}
+
+		force := aMass.Value / (dist * dist) * 500
+
+		ax += dx / dist * force
+		ay += dy / dist * force
 	}
+	return ax, ay
+}
 
-	return ecs.StateEngineContinue
+// UseStore makes the system attract the particles of store.
+func (s *gravitySystem) UseStore(store *ParticleStore) {
+	s.store = store
+}
+
+// SetParallel spreads the work over pool (nil runs sequentially).
+func (s *gravitySystem) SetParallel(pool *WorkerPool) {
+	s.workers = pool
 }
 
 func (s *gravitySystem) Teardown() {}
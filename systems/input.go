@@ -1,6 +1,8 @@
 package systems
 
 import (
+	"math"
+
 	"github.com/andygeiss/ecs"
 	"github.com/deltatree/showcase/components"
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -13,21 +15,33 @@ import (
 //   - Right click (hold): repel particles from cursor
 //   - Double-click left: toggle attract lock (continuous attraction)
 //   - Double-click right: toggle repel lock (continuous repulsion)
+//   - Wheel: zoom the camera around the cursor
+//   - Middle drag, or left drag while holding Space: pan the camera
+//
+// The mouse attractor follows the cursor in world coordinates, so it stays
+// under the cursor however the camera is zoomed, panned or rotated. The
+// callbacks set by SetOnAttractLock and SetOnRepelRelease learn where the
+// attract lock was engaged and the repel button released.
 //
 // Keyboard controls:
 //   - 1-5: switch between presets
+//   - T: form / release a morph figure (see SetOnMorph)
+//   - C: reset the camera
 //   - F3: toggle debug overlay (handled by RenderSystem)
 type inputSystem struct {
 	mouseAttractorID string
 	currentPreset    int
 	presetSwitcher   func(int)
+	onMorph          func()
+	onAttractLock    func(x, y float32)
+	onRepelRelease   func(x, y float32)
 	lockedMode       int     // 0=none, 1=attract, -1=repel
 	lastClickTime    float64 // for double-click detection
 }
 
 // NewInputSystem creates a new input system with a preset switcher callback.
 // The callback is invoked with the preset index (0-4) when keys 1-5 are pressed.
-func NewInputSystem(presetSwitcher func(int)) ecs.System {
+func NewInputSystem(presetSwitcher func(int)) *inputSystem {
 	return &inputSystem{
 		mouseAttractorID: "mouse-attractor",
 		currentPreset:    0,
@@ -42,6 +56,13 @@ func (s *inputSystem) Setup() {}
 func (s *inputSystem) Process(em ecs.EntityManager) (state int) {
 	mouseX := float32(rl.GetMouseX())
 	mouseY := float32(rl.GetMouseY())
+	width, height := float32(rl.GetScreenWidth()), float32(rl.GetScreenHeight())
+
+	panning := rl.IsKeyDown(rl.KeySpace)
+	if cam := findCamera(em); cam != nil {
+		s.updateCamera(cam, mouseX, mouseY, width, height, panning)
+		mouseX, mouseY = cam.ScreenToWorld(mouseX, mouseY, width, height)
+	}
 
 	// Find or create mouse attractor
 	attractor := em.Get(s.mouseAttractorID)
@@ -63,13 +84,16 @@ func (s *inputSystem) Process(em ecs.EntityManager) (state int) {
 	currentTime := rl.GetTime()
 
 	// Double-click detection for locking
-	if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) && !panning {
 		if currentTime-s.lastClickTime < 0.3 {
 			// Double-click: toggle lock
 			if s.lockedMode == 1 {
 				s.lockedMode = 0
 			} else {
 				s.lockedMode = 1
+				if s.onAttractLock != nil {
+					s.onAttractLock(mouseX, mouseY)
+				}
 			}
 		}
 		s.lastClickTime = currentTime
@@ -85,9 +109,15 @@ func (s *inputSystem) Process(em ecs.EntityManager) (state int) {
 		}
 		s.lastClickTime = currentTime
 	}
+	if rl.IsMouseButtonReleased(rl.MouseRightButton) && s.lockedMode == 0 && s.onRepelRelease != nil {
+		s.onRepelRelease(mouseX, mouseY)
+	}
 
-	// Apply mass based on locked mode or current button state
-	if s.lockedMode == 1 {
+	// Apply mass based on locked mode or current button state; while
+	// panning with Space the left button drags the camera instead
+	if panning && s.lockedMode == 0 {
+		mass.Value = 0
+	} else if s.lockedMode == 1 {
 		mass.Value = 8000 // Locked attract
 	} else if s.lockedMode == -1 {
 		mass.Value = -8000 // Locked repel
@@ -123,7 +153,50 @@ func (s *inputSystem) Process(em ecs.EntityManager) (state int) {
 		}
 	}
 
+	if s.onMorph != nil && rl.IsKeyPressed(rl.KeyT) {
+		s.onMorph()
+	}
+
 	return ecs.StateEngineContinue
 }
 
 func (s *inputSystem) Teardown() {}
+
+// zoomStep is the zoom factor of one mouse wheel notch.
+const zoomStep = 1.1
+
+// updateCamera zooms, pans and resets the camera. Panning by hand stops
+// the camera from following an entity.
+func (s *inputSystem) updateCamera(cam *components.Camera, mouseX, mouseY, width, height float32, panning bool) {
+	if wheel := rl.GetMouseWheelMove(); wheel != 0 {
+		cam.ZoomAt(float32(math.Pow(zoomStep, float64(wheel))), mouseX, mouseY, width, height)
+	}
+
+	if rl.IsMouseButtonDown(rl.MouseMiddleButton) || (panning && rl.IsMouseButtonDown(rl.MouseLeftButton)) {
+		if delta := rl.GetMouseDelta(); delta.X != 0 || delta.Y != 0 {
+			cam.Pan(delta.X, delta.Y)
+			cam.Follow = ""
+		}
+	}
+
+	if rl.IsKeyPressed(rl.KeyC) {
+		cam.With(width/2, height/2).WithZoom(1).WithRotation(0)
+	}
+}
+
+// SetOnMorph sets the callback invoked when the morph key (T) is pressed.
+func (s *inputSystem) SetOnMorph(callback func()) {
+	s.onMorph = callback
+}
+
+// SetOnAttractLock sets the callback invoked with the world position of
+// the cursor when a double-click locks the attractor.
+func (s *inputSystem) SetOnAttractLock(callback func(x, y float32)) {
+	s.onAttractLock = callback
+}
+
+// SetOnRepelRelease sets the callback invoked with the world position of
+// the cursor when the repelling button is released.
+func (s *inputSystem) SetOnRepelRelease(callback func(x, y float32)) {
+	s.onRepelRelease = callback
+}
@@ -0,0 +1,203 @@
+package systems
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/andygeiss/ecs"
+	"github.com/deltatree/showcase/components"
+	"github.com/deltatree/showcase/premium"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// JuiceEvent is a moment the screen reacts to with juice.
+type JuiceEvent int
+
+// Events the JuiceSystem reacts to.
+const (
+	// JuicePresetSwitch is a switch to another preset.
+	JuicePresetSwitch JuiceEvent = iota
+	// JuiceAttractLock is a double-click locking the mouse attractor.
+	JuiceAttractLock
+	// JuiceRepelRelease is the release of the repelling mouse button.
+	JuiceRepelRelease
+	// JuiceMassDeath is a large share of the particles expiring at once.
+	JuiceMassDeath
+)
+
+// juiceReaction is the reaction to an event at full strength; the juice
+// level scales it.
+type juiceReaction struct {
+	shake, shakeDuration float32
+	pulse, pulseDuration float32 // Zoom at the peak of the pulse
+	flash, flashDuration float32 // Flash opacity
+	// burstSpeed is the speed of burst particles spawned at the event
+	// position: outwards, or inwards from a ring if negative. 0 spawns
+	// no burst.
+	burstSpeed float32
+}
+
+var juiceReactions = [...]juiceReaction{
+	JuicePresetSwitch: {shake: 4, shakeDuration: 0.2, pulse: 1.06, pulseDuration: 0.3, flash: 0.3, flashDuration: 0.25},
+	JuiceAttractLock:  {shake: 3, shakeDuration: 0.15, pulse: 1.04, pulseDuration: 0.2, burstSpeed: -120},
+	JuiceRepelRelease: {shake: 8, shakeDuration: 0.25, pulse: 0.97, pulseDuration: 0.2, burstSpeed: 250},
+	JuiceMassDeath:    {shake: 6, shakeDuration: 0.3, pulse: 1.03, pulseDuration: 0.25, flash: 0.2, flashDuration: 0.3},
+}
+
+// Mass deaths are at least massDeathMin particles and massDeathShare of
+// the population expiring in one frame, at most once per
+// massDeathCooldown seconds.
+const (
+	massDeathMin      = 100
+	massDeathShare    = 0.2
+	massDeathCooldown = 1.0
+	// burstRing is the radius inward bursts start on.
+	burstRing = 60
+)
+
+// juiceScreen receives the screen effects of the JuiceSystem, usually the
+// RenderSystem.
+type juiceScreen interface {
+	ApplyShake(intensity, duration float32)
+	ApplyPulse(scale, duration float32)
+	ApplyFlash(alpha, duration float32)
+}
+
+// juiceTrigger is a queued event at a world position.
+type juiceTrigger struct {
+	event JuiceEvent
+	x, y  float32
+}
+
+// JuiceSystem gives feedback on events: it shakes, pulses (zooms) and
+// flashes the screen and spawns click bursts, scaled by the multipliers of
+// the premium.JuiceConfig of its juice level.
+//
+// Events are triggered by other systems' callbacks (see Trigger and
+// MassDeath) and handled in the system's next update, so bursts are
+// spawned in the regular entity flow. The J key cycles the juice level.
+type juiceSystem struct {
+	screen        juiceScreen
+	config        premium.JuiceConfig
+	palette       premium.ColorPalette
+	pending       []juiceTrigger
+	cooldown      float32 // Until the next mass death reaction
+	onLevelChange func(premium.JuiceLevel)
+}
+
+// NewJuiceSystem creates a juice system reacting on screen at the given
+// level.
+func NewJuiceSystem(screen juiceScreen, level premium.JuiceLevel) *juiceSystem {
+	return &juiceSystem{
+		screen:  screen,
+		config:  premium.GetJuiceConfig(level),
+		palette: premium.GalaxyPalette,
+	}
+}
+
+func (s *juiceSystem) Setup() {}
+
+func (s *juiceSystem) Process(em ecs.EntityManager) (state int) {
+	if rl.IsKeyPressed(rl.KeyJ) {
+		s.SetLevel(premium.NextJuice(s.config.Level))
+		if s.onLevelChange != nil {
+			s.onLevelChange(s.config.Level)
+		}
+	}
+	s.update(em, rl.GetFrameTime())
+	return ecs.StateEngineContinue
+}
+
+// update reacts to the events triggered since the last update.
+func (s *juiceSystem) update(em ecs.EntityManager, dt float32) {
+	s.cooldown = max(s.cooldown-dt, 0)
+	for _, t := range s.pending {
+		s.react(em, t)
+	}
+	clear(s.pending)
+	s.pending = s.pending[:0]
+}
+
+// react applies the scaled reaction to one event.
+func (s *juiceSystem) react(em ecs.EntityManager, t juiceTrigger) {
+	if int(t.event) >= len(juiceReactions) || t.event < 0 {
+		return
+	}
+	r := juiceReactions[t.event]
+	c := s.config
+	if r.shake > 0 && c.ShakeMultiplier > 0 {
+		s.screen.ApplyShake(r.shake*c.ShakeMultiplier, r.shakeDuration)
+	}
+	if r.pulse != 1 && c.PulseMultiplier > 0 {
+		s.screen.ApplyPulse(1+(r.pulse-1)*c.PulseMultiplier, r.pulseDuration)
+	}
+	if r.flash > 0 && c.FlashEnabled {
+		s.screen.ApplyFlash(r.flash, r.flashDuration)
+	}
+	if r.burstSpeed != 0 && c.ParticleBurst > 0 {
+		s.burst(em, t.x, t.y, r.burstSpeed, c.ParticleBurst)
+	}
+}
+
+// burst spawns n particles in the palette's colors at (x, y), flying
+// outwards at speed, or inwards from a ring if speed is negative.
+func (s *juiceSystem) burst(em ecs.EntityManager, x, y, speed float32, n int) {
+	p := s.palette
+	for i := 0; i < n; i++ {
+		angle := (float64(i) + rand.Float64()*0.5) / float64(n) * 2 * math.Pi
+		dx, dy := float32(math.Cos(angle)), float32(math.Sin(angle))
+		px, py := x, y
+		if speed < 0 {
+			px, py = x+dx*burstRing, y+dy*burstRing
+		}
+		v := speed * (0.6 + rand.Float32()*0.4)
+		em.Add(ecs.NewEntity(components.NewParticleID(), []ecs.Component{
+			components.NewPosition().With(px, py),
+			components.NewVelocity().With(dx*v, dy*v),
+			components.NewAcceleration(),
+			components.NewColor().WithGradient(p.StartR, p.StartG, p.StartB, 255, p.EndR, p.EndG, p.EndB, 0).WithSpace(p.Space),
+			components.NewLifetime().WithTTL(0.5 + rand.Float32()*0.4),
+			components.NewSize().WithRadius(2 + rand.Float32()*1.5).WithEndSize(0.5),
+			components.NewParticle(),
+		}))
+	}
+}
+
+func (s *juiceSystem) Teardown() {}
+
+// Trigger queues an event at the world position (x, y); the position
+// matters for events with a burst.
+func (s *juiceSystem) Trigger(event JuiceEvent, x, y float32) {
+	s.pending = append(s.pending, juiceTrigger{event, x, y})
+}
+
+// MassDeath reports that expired of total particles expired in one frame,
+// triggering JuiceMassDeath if they are enough.
+func (s *juiceSystem) MassDeath(expired, total int) {
+	if s.cooldown > 0 || expired < massDeathMin || float32(expired) < massDeathShare*float32(total) {
+		return
+	}
+	s.cooldown = massDeathCooldown
+	s.Trigger(JuiceMassDeath, 0, 0)
+}
+
+// SetLevel sets the juice level.
+func (s *juiceSystem) SetLevel(level premium.JuiceLevel) {
+	s.config = premium.GetJuiceConfig(level)
+}
+
+// Level returns the juice level.
+func (s *juiceSystem) Level() premium.JuiceLevel {
+	return s.config.Level
+}
+
+// SetPalette sets the colors of burst particles.
+func (s *juiceSystem) SetPalette(palette premium.ColorPalette) {
+	s.palette = palette
+}
+
+// SetOnLevelChange sets the callback for when the user cycles the juice
+// level.
+func (s *juiceSystem) SetOnLevelChange(callback func(premium.JuiceLevel)) {
+	s.onLevelChange = callback
+}
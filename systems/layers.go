@@ -0,0 +1,208 @@
+package systems
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/andygeiss/ecs"
+	"github.com/deltatree/showcase/components"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// layerState holds the render settings of one layer.
+type layerState struct {
+	visible bool
+	blend   components.BlendMode
+}
+
+// layerOf returns the render layer and Z of an entity. Entities without a
+// Layer component belong to the particles layer at Z 0.
+func layerOf(e *ecs.Entity) (components.RenderLayer, float32) {
+	if e.Masked&components.MaskLayer == 0 {
+		return components.LayerParticles, 0
+	}
+	l := e.Get(components.MaskLayer).(*components.Layer)
+	if l.Layer >= components.LayerCount {
+		return components.LayerParticles, l.Z
+	}
+	return l.Layer, l.Z
+}
+
+// compareZ orders entities by the Z of their layer.
+func compareZ(a, b *ecs.Entity) int {
+	_, za := layerOf(a)
+	_, zb := layerOf(b)
+	return cmp.Compare(za, zb)
+}
+
+// bucketLayers sorts the renderable entities (and attractors, if gizmos are
+// shown) into their layers and returns the number of renderable entities.
+// Layers with Z values are stably sorted, so entities with equal Z keep
+// their query order.
+func (s *renderSystem) bucketLayers(em ecs.EntityManager) int {
+	var sorted [components.LayerCount]bool
+	entities := query(em, components.MaskRenderable)
+	for _, e := range entities {
+		layer, z := layerOf(e)
+		s.buckets[layer] = append(s.buckets[layer], e)
+		sorted[layer] = sorted[layer] || z != 0
+	}
+	for layer, bucket := range s.buckets {
+		if sorted[layer] {
+			slices.SortStableFunc(bucket, compareZ)
+		}
+	}
+
+	if s.showGizmos {
+		for _, e := range query(em, components.MaskAttractor|components.MaskPosition|components.MaskMass) {
+			layer := components.LayerForeground
+			if e.Masked&components.MaskLayer != 0 {
+				layer, _ = layerOf(e)
+			}
+			s.gizmos[layer] = append(s.gizmos[layer], e)
+		}
+	}
+	return len(entities)
+}
+
+// drawLayer draws the entities of a layer offset by the screen shake and
+// empties its buckets. Trails go underneath the layer's entities, gizmos
+// on top. The particles layer also draws the particle store, and the
+// metaballs in place of the particles they were drawn from.
+func (s *renderSystem) drawLayer(layer components.RenderLayer, shakeX, shakeY float32) {
+	entities, gizmos := s.buckets[layer], s.gizmos[layer]
+	defer func() {
+		clear(entities)
+		s.buckets[layer] = entities[:0]
+		clear(gizmos)
+		s.gizmos[layer] = gizmos[:0]
+	}()
+	state := s.layers[layer]
+	if !state.visible {
+		return
+	}
+
+	// Entities without a Blend component use the layer's blend mode;
+	// the others are queued and drawn afterwards, grouped by mode
+	s.beginBlendMode(state.blend)
+
+	if s.passes&PassTrails != 0 && s.trails.IsEnabled() {
+		for _, e := range entities {
+			if e.Masked&components.MaskTrail == 0 {
+				continue
+			}
+			s.trails.RenderTrail(
+				e.Get(components.MaskPosition).(*components.Position),
+				e.Get(components.MaskTrail).(*components.Trail),
+				e.Get(components.MaskColor).(*components.Color),
+				e.Get(components.MaskSize).(*components.Size),
+				shakeX, shakeY,
+			)
+		}
+	}
+
+	metaballs := s.metaballsActive && layer == components.LayerParticles
+	for _, e := range entities {
+		if metaballs && s.isMetaball(e) {
+			continue
+		}
+		if e.Masked&components.MaskBlend != 0 {
+			if mode := e.Get(components.MaskBlend).(*components.Blend).Mode; mode != state.blend && int(mode) < len(s.blendQueues) {
+				s.blendQueues[mode] = append(s.blendQueues[mode], e)
+				continue
+			}
+		}
+		s.drawEntity(e, shakeX, shakeY)
+	}
+
+	if st := s.store; st != nil && layer == components.LayerParticles && !metaballs {
+		for i := range st.X {
+			c := st.Color[i]
+			s.drawParticle(st.X[i]+shakeX, st.Y[i]+shakeY, st.VX[i], st.VY[i], st.Radius[i], c[0], c[1], c[2], c[3])
+		}
+	}
+	s.flush()
+	if metaballs {
+		// The density texture covers the screen, not the world
+		rl.EndMode2D()
+		s.metaballs.Composite(float32(s.width), float32(s.height))
+		rl.BeginMode2D(s.camera)
+	}
+	rl.EndBlendMode()
+
+	for mode, queue := range s.blendQueues {
+		if len(queue) == 0 {
+			continue
+		}
+		s.beginBlendMode(components.BlendMode(mode))
+		for _, e := range queue {
+			s.drawEntity(e, shakeX, shakeY)
+		}
+		s.flush()
+		rl.EndBlendMode()
+		clear(queue)
+		s.blendQueues[mode] = queue[:0]
+	}
+
+	for _, e := range gizmos {
+		drawGizmo(e, shakeX, shakeY)
+	}
+}
+
+// drawGizmo draws an attractor as a ring growing with its mass: blue for
+// attraction, red for repulsion. Inactive attractors (mass 0) are skipped.
+func drawGizmo(e *ecs.Entity, shakeX, shakeY float32) {
+	mass := e.Get(components.MaskMass).(*components.Mass).Value
+	if mass == 0 {
+		return
+	}
+	pos := e.Get(components.MaskPosition).(*components.Position)
+	center := rl.NewVector2(pos.X+shakeX, pos.Y+shakeY)
+
+	col := rl.NewColor(100, 180, 255, 200)
+	if mass < 0 {
+		col = rl.NewColor(255, 110, 100, 200)
+	}
+	radius := 6 + float32(math.Sqrt(math.Abs(float64(mass))))/4
+	rl.DrawCircleLinesV(center, radius, col)
+	rl.DrawCircleV(center, 2, col)
+}
+
+// SetLayerVisible shows or hides a render layer.
+func (s *renderSystem) SetLayerVisible(layer components.RenderLayer, visible bool) {
+	if layer < components.LayerCount {
+		s.layers[layer].visible = visible
+	}
+}
+
+// IsLayerVisible returns whether a render layer is drawn.
+func (s *renderSystem) IsLayerVisible(layer components.RenderLayer) bool {
+	return layer < components.LayerCount && s.layers[layer].visible
+}
+
+// SetLayerBlendMode sets the blend mode for entities of a layer without a
+// Blend component.
+func (s *renderSystem) SetLayerBlendMode(layer components.RenderLayer, mode components.BlendMode) {
+	if layer < components.LayerCount {
+		s.layers[layer].blend = mode
+	}
+}
+
+// LayerBlendMode returns the blend mode of a layer.
+func (s *renderSystem) LayerBlendMode(layer components.RenderLayer) components.BlendMode {
+	if layer < components.LayerCount {
+		return s.layers[layer].blend
+	}
+	return components.BlendAlpha
+}
+
+// SetGizmos shows or hides the attractor gizmos.
+func (s *renderSystem) SetGizmos(show bool) {
+	s.showGizmos = show
+}
+
+// GizmosVisible returns whether attractor gizmos are drawn.
+func (s *renderSystem) GizmosVisible() bool {
+	return s.showGizmos
+}
@@ -13,23 +13,54 @@ import (
 //
 // This system enables particle effects with finite durations, preventing
 // unbounded entity accumulation and enabling effects like fading trails.
-type lifetimeSystem struct{}
+//
+// Entities holding an active Target do not age, so particles that form a
+// figure stay alive until the figure is released.
+//
+// When a ParticlePool is attached with SetPool, removed entities are
+// returned to the pool for reuse by the EmitterSystem. With a CommandBuffer
+// attached via SetCommands, removals are recorded and applied when the
+// buffer is flushed. Particles of an attached ParticleStore are aged and
+// killed in place.
+//
+// The callback set by SetOnExpire learns how many particles expired each
+// frame, e.g. to react to mass deaths.
+type lifetimeSystem struct {
+	pool     *ParticlePool
+	store    *ParticleStore
+	commands *CommandBuffer
+	onExpire func(expired, total int)
+}
 
 // NewLifetimeSystem creates a new lifetime system.
-func NewLifetimeSystem() ecs.System {
+func NewLifetimeSystem() *lifetimeSystem {
 	return &lifetimeSystem{}
 }
 
+// SetPool sets the pool expired particles are returned to (nil disables
+// recycling).
+func (s *lifetimeSystem) SetPool(pool *ParticlePool) {
+	s.pool = pool
+}
+
 func (s *lifetimeSystem) Setup() {}
 
 func (s *lifetimeSystem) Process(em ecs.EntityManager) (state int) {
-	dt := rl.GetFrameTime()
+	s.update(em, rl.GetFrameTime())
+	return ecs.StateEngineContinue
+}
 
-	entities := em.FilterByMask(components.MaskLifetime)
+// update ages all entities by dt seconds and removes the expired ones.
+func (s *lifetimeSystem) update(em ecs.EntityManager, dt float32) {
+	entities := query(em, components.MaskLifetime)
 
 	var toRemove []*ecs.Entity
 
 	for _, e := range entities {
+		if t := e.Get(components.MaskTarget); t != nil && t.(*components.Target).Active {
+			continue
+		}
+
 		life := e.Get(components.MaskLifetime).(*components.Lifetime)
 		life.Age += dt
 
@@ -40,10 +71,51 @@ func (s *lifetimeSystem) Process(em ecs.EntityManager) (state int) {
 	}
 
 	for _, entity := range toRemove {
-		em.Remove(entity)
+		if s.commands != nil {
+			s.commands.Remove(entity)
+			continue
+		}
+		if removeEntity(em, entity) && s.pool != nil {
+			s.pool.Release(entity)
+		}
 	}
 
-	return ecs.StateEngineContinue
+	total, expired := len(entities), len(toRemove)
+	if st := s.store; st != nil {
+		total += st.Len()
+		for i := 0; i < st.Len(); {
+			st.Age[i] += dt
+			if st.Age[i] >= st.TTL[i] {
+				// Kill moves the last particle into slot i, which is
+				// visited next without advancing.
+				st.Kill(i)
+				expired++
+				continue
+			}
+			i++
+		}
+	}
+
+	if expired > 0 && s.onExpire != nil {
+		s.onExpire(expired, total)
+	}
+}
+
+// SetCommands makes the system record removals into buffer instead of
+// removing entities immediately. Recycling is then done by the buffer.
+func (s *lifetimeSystem) SetCommands(buffer *CommandBuffer) {
+	s.commands = buffer
+}
+
+// SetOnExpire sets the callback invoked with the number of particles that
+// expired in a frame and the number of particles aged.
+func (s *lifetimeSystem) SetOnExpire(callback func(expired, total int)) {
+	s.onExpire = callback
+}
+
+// UseStore makes the system age the particles of store.
+func (s *lifetimeSystem) UseStore(store *ParticleStore) {
+	s.store = store
 }
 
 func (s *lifetimeSystem) Teardown() {}
@@ -0,0 +1,200 @@
+package systems
+
+import (
+	"github.com/deltatree/showcase/components"
+	"github.com/deltatree/showcase/premium"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// lightDownsample divides the screen size for the light buffer. Light
+// varies slowly, so a coarse buffer filtered bilinearly looks the same.
+const lightDownsample = 4
+
+// lightTint returns the tint a particle's light is drawn with: its color
+// weighted by its alpha and the lighting intensity.
+func lightTint(r, g, b, a uint8, intensity float32) (uint8, uint8, uint8, uint8) {
+	w := float32(a) / 255 * intensity
+	scale := func(c uint8) uint8 {
+		return uint8(min(float32(c)*w+0.5, 255))
+	}
+	return scale(r), scale(g), scale(b), 255
+}
+
+// LightRenderer lights the scenery with the particles as light sources.
+// Each particle adds a soft radial light in its color to a low-resolution
+// light buffer cleared to the ambient light. The background and the
+// background layer are drawn into a separate texture and multiplied with
+// the light buffer; that lit texture then replaces them in the scene, so
+// the particles themselves are not darkened.
+//
+// Both textures are drawn before the scene, as render textures cannot
+// nest. Lighting is off until SetLighting sets a configuration.
+//
+// GPU resources are created by Load once the window exists and released
+// by Unload.
+type LightRenderer struct {
+	config *premium.Lighting
+	sheet  *SpriteSheet // Light falloff
+
+	width, height int32
+	loaded        bool
+
+	lights, lit rl.RenderTexture2D
+}
+
+// NewLightRenderer creates a light renderer for a screen of the given
+// size.
+func NewLightRenderer(width, height int32) *LightRenderer {
+	return &LightRenderer{sheet: newFalloffSheet(), width: width, height: height}
+}
+
+// Load creates the render textures. It must be called after the window
+// has been created.
+func (r *LightRenderer) Load() {
+	if r.loaded {
+		return
+	}
+	r.loadTargets()
+	r.loaded = true
+}
+
+func (r *LightRenderer) loadTargets() {
+	w, h := r.bufferSize()
+	r.lights = rl.LoadRenderTexture(w, h)
+	rl.SetTextureFilter(r.lights.Texture, rl.FilterBilinear)
+	r.lit = rl.LoadRenderTexture(r.width, r.height)
+}
+
+func (r *LightRenderer) unloadTargets() {
+	rl.UnloadRenderTexture(r.lights)
+	rl.UnloadRenderTexture(r.lit)
+}
+
+// bufferSize returns the size of the light buffer.
+func (r *LightRenderer) bufferSize() (int32, int32) {
+	return max(r.width/lightDownsample, 1), max(r.height/lightDownsample, 1)
+}
+
+// Unload releases the GPU resources.
+func (r *LightRenderer) Unload() {
+	r.sheet.Unload()
+	if !r.loaded {
+		return
+	}
+	r.unloadTargets()
+	r.loaded = false
+}
+
+// Active reports whether the scenery is lit.
+func (r *LightRenderer) Active() bool {
+	return r.config != nil && r.loaded
+}
+
+// BeginLights redirects drawing into the light buffer, cleared to the
+// ambient light and seen through camera, and sets the additive blending
+// lights are summed with. Every call must be paired with EndLights.
+func (r *LightRenderer) BeginLights(camera rl.Camera2D) {
+	rl.BeginTextureMode(r.lights)
+	rl.ClearBackground(rl.NewColor(r.config.AmbientR, r.config.AmbientG, r.config.AmbientB, 255))
+	rl.SetBlendFactors(rl.One, rl.One, rl.FuncAdd)
+	rl.BeginBlendMode(rl.BlendCustom)
+
+	camera.Offset = rl.NewVector2(camera.Offset.X/lightDownsample, camera.Offset.Y/lightDownsample)
+	camera.Zoom /= lightDownsample
+	rl.BeginMode2D(camera)
+}
+
+// draw adds the light of a particle at (x, y) with the given radius.
+func (r *LightRenderer) draw(d particleDrawer, x, y, radius float32, cr, cg, cb, ca uint8) {
+	cr, cg, cb, ca = lightTint(cr, cg, cb, ca, r.config.Intensity)
+	d.Sprite(r.sheet, 0, x, y, radius*r.config.Radius, 0, cr, cg, cb, ca)
+}
+
+// EndLights finishes the light buffer. Queued lights must be flushed
+// before.
+func (r *LightRenderer) EndLights() {
+	rl.EndMode2D()
+	rl.EndBlendMode()
+	rl.EndTextureMode()
+}
+
+// BeginLit redirects drawing of the scenery into the lit texture, cleared
+// with background. Every call must be paired with EndLit.
+func (r *LightRenderer) BeginLit(background rl.Color) {
+	rl.BeginTextureMode(r.lit)
+	rl.ClearBackground(background)
+}
+
+// EndLit multiplies the scenery with the light buffer and finishes the
+// lit texture.
+func (r *LightRenderer) EndLit() {
+	rl.BeginBlendMode(rl.BlendMultiplied)
+	drawTarget(r.lights, float32(r.width), float32(r.height))
+	rl.EndBlendMode()
+	rl.EndTextureMode()
+}
+
+// DrawLit draws the lit scenery over the current target at the given
+// opacity.
+func (r *LightRenderer) DrawLit(alpha uint8) {
+	src := rl.NewRectangle(0, 0, float32(r.lit.Texture.Width), -float32(r.lit.Texture.Height))
+	dst := rl.NewRectangle(0, 0, float32(r.width), float32(r.height))
+	rl.DrawTexturePro(r.lit.Texture, src, dst, rl.Vector2{}, 0, rl.NewColor(255, 255, 255, alpha))
+}
+
+// SetLighting sets the lighting configuration; nil turns lighting off.
+func (r *LightRenderer) SetLighting(config *premium.Lighting) {
+	r.config = config
+}
+
+// Lighting returns the lighting configuration, nil if lighting is off.
+func (r *LightRenderer) Lighting() *premium.Lighting {
+	return r.config
+}
+
+// Resize sets the screen size, recreating the render textures if they
+// are loaded.
+func (r *LightRenderer) Resize(width, height int32) {
+	if width == r.width && height == r.height {
+		return
+	}
+	r.width, r.height = width, height
+	if r.loaded {
+		r.unloadTargets()
+		r.loadTargets()
+	}
+}
+
+// drawLights draws the light of the particles layer into the light buffer,
+// offset by the screen shake. It runs before the scene is drawn, once the
+// layers are bucketed.
+func (s *renderSystem) drawLights(camera rl.Camera2D, shakeX, shakeY float32) {
+	s.lights.BeginLights(camera)
+	d := s.drawer()
+	for _, e := range s.buckets[components.LayerParticles] {
+		pos := e.Get(components.MaskPosition).(*components.Position)
+		col := e.Get(components.MaskColor).(*components.Color)
+		size := e.Get(components.MaskSize).(*components.Size)
+		s.lights.draw(d, pos.X+shakeX, pos.Y+shakeY, size.Radius, col.R, col.G, col.B, col.A)
+	}
+	if st := s.store; st != nil {
+		for i := range st.X {
+			c := st.Color[i]
+			s.lights.draw(d, st.X[i]+shakeX, st.Y[i]+shakeY, st.Radius[i], c[0], c[1], c[2], c[3])
+		}
+	}
+	s.flush()
+	s.lights.EndLights()
+}
+
+// drawLit draws the background and the background layer into the lit
+// texture and lights them. The background layer's buckets are emptied, so
+// the scene draws only the lit texture in their place.
+func (s *renderSystem) drawLit(camera rl.Camera2D, panX, panY, now, shakeX, shakeY float32) {
+	s.lights.BeginLit(s.background.Color())
+	s.background.Draw(panX, panY, now, 255)
+	rl.BeginMode2D(camera)
+	s.drawLayer(components.LayerBackground, shakeX, shakeY)
+	rl.EndMode2D()
+	s.lights.EndLit()
+}
@@ -0,0 +1,248 @@
+package systems
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/andygeiss/ecs"
+	"github.com/deltatree/showcase/components"
+	"github.com/deltatree/showcase/premium"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// metaballShader turns the density texture into blobs. Color channels hold
+// the density-weighted particle colors, alpha the density, so dividing
+// them gives the blended color of the particles around each pixel.
+const metaballShader = `#version 330
+in vec2 fragTexCoord;
+in vec4 fragColor;
+uniform sampler2D texture0;
+uniform float threshold;
+uniform float edge;
+out vec4 finalColor;
+
+void main() {
+	vec4 d = texture(texture0, fragTexCoord);
+	float k = smoothstep(threshold - edge, threshold + edge, d.a);
+	vec3 c = d.rgb / max(d.a, 0.0001);
+	// A bright rim just inside the surface gives the blobs a liquid sheen
+	float rim = k * (1.0 - smoothstep(threshold, threshold + 4.0 * edge, d.a));
+	c = mix(c, vec3(1.0), rim * 0.35);
+	finalColor = vec4(c, k) * fragColor;
+}
+`
+
+const (
+	// metaballDownsample divides the screen size for the density texture;
+	// bilinear filtering keeps the blob outlines smooth.
+	metaballDownsample = 2
+	// metaballDensityScale scales the density stored in the texture, so
+	// it saturates at 2 instead of 1.
+	metaballDensityScale = 0.5
+	// falloffSize is the edge length of the falloff texture.
+	falloffSize = 64
+)
+
+// metaballFalloff returns the density of a particle at distance d, as a
+// fraction of its spread, from its center: 1 at the center, falling
+// smoothly to 0 at d = 1.
+func metaballFalloff(d float64) float64 {
+	if d >= 1 {
+		return 0
+	}
+	f := 1 - d*d
+	return f * f
+}
+
+// newFalloffSheet creates the texture a particle's density is drawn with.
+// Color and alpha both hold the falloff, so additively drawn particles sum
+// up color × density and density.
+func newFalloffSheet() *SpriteSheet {
+	img := image.NewNRGBA(image.Rect(0, 0, falloffSize, falloffSize))
+	const c = falloffSize / 2
+	for y := 0; y < falloffSize; y++ {
+		for x := 0; x < falloffSize; x++ {
+			dx, dy := float64(x)+0.5-c, float64(y)+0.5-c
+			v := uint8(metaballFalloff(math.Hypot(dx, dy)/c)*255 + 0.5)
+			img.SetNRGBA(x, y, color.NRGBA{v, v, v, v})
+		}
+	}
+	return NewSpriteSheet(img, falloffSize, falloffSize)
+}
+
+// metaballTint returns the tint a particle's falloff is drawn with: the
+// color weighted by its alpha, all scaled to the stored density range.
+func metaballTint(r, g, b, a uint8) (uint8, uint8, uint8, uint8) {
+	w := float32(a) / 255 * metaballDensityScale
+	return uint8(float32(r)*w + 0.5), uint8(float32(g)*w + 0.5), uint8(float32(b)*w + 0.5), uint8(float32(a)*metaballDensityScale + 0.5)
+}
+
+// MetaballRenderer draws particles as merging liquid blobs. Each particle
+// adds a soft radial falloff to a density texture; a threshold shader then
+// draws the texture, filling everything above the threshold density with
+// the blended particle colors.
+//
+// The density is drawn before the scene (render textures cannot nest) and
+// composited in place of the particles. Metaballs are off until
+// SetMetaballs sets a configuration.
+//
+// GPU resources are created by Load once the window exists and released
+// by Unload.
+type MetaballRenderer struct {
+	config *premium.Metaballs
+	sheet  *SpriteSheet
+
+	width, height int32
+	loaded        bool
+
+	density               rl.RenderTexture2D
+	shader                rl.Shader
+	thresholdLoc, edgeLoc int32
+}
+
+// NewMetaballRenderer creates a metaball renderer for a screen of the
+// given size.
+func NewMetaballRenderer(width, height int32) *MetaballRenderer {
+	return &MetaballRenderer{sheet: newFalloffSheet(), width: width, height: height}
+}
+
+// Load compiles the shader and creates the density texture. It must be
+// called after the window has been created. If the shader cannot be
+// compiled, particles stay circles.
+func (r *MetaballRenderer) Load() {
+	if r.loaded {
+		return
+	}
+	r.shader = rl.LoadShaderFromMemory("", metaballShader)
+	if !rl.IsShaderValid(r.shader) {
+		return
+	}
+	r.thresholdLoc = rl.GetShaderLocation(r.shader, "threshold")
+	r.edgeLoc = rl.GetShaderLocation(r.shader, "edge")
+	r.loadTarget()
+	r.loaded = true
+}
+
+func (r *MetaballRenderer) loadTarget() {
+	w, h := r.densitySize()
+	r.density = rl.LoadRenderTexture(w, h)
+	rl.SetTextureFilter(r.density.Texture, rl.FilterBilinear)
+}
+
+// densitySize returns the size of the density texture.
+func (r *MetaballRenderer) densitySize() (int32, int32) {
+	return max(r.width/metaballDownsample, 1), max(r.height/metaballDownsample, 1)
+}
+
+// Unload releases the GPU resources.
+func (r *MetaballRenderer) Unload() {
+	r.sheet.Unload()
+	if !r.loaded {
+		return
+	}
+	rl.UnloadRenderTexture(r.density)
+	rl.UnloadShader(r.shader)
+	r.loaded = false
+}
+
+// Active reports whether particles are drawn as metaballs.
+func (r *MetaballRenderer) Active() bool {
+	return r.config != nil && r.loaded
+}
+
+// Begin redirects drawing into the cleared density texture, seen through
+// camera, and sets the additive blending densities are summed with. Every
+// call must be paired with End.
+func (r *MetaballRenderer) Begin(camera rl.Camera2D) {
+	rl.BeginTextureMode(r.density)
+	rl.ClearBackground(rl.Blank)
+	rl.SetBlendFactors(rl.One, rl.One, rl.FuncAdd)
+	rl.BeginBlendMode(rl.BlendCustom)
+
+	camera.Offset = rl.NewVector2(camera.Offset.X/metaballDownsample, camera.Offset.Y/metaballDownsample)
+	camera.Zoom /= metaballDownsample
+	rl.BeginMode2D(camera)
+}
+
+// draw adds the density of a particle at (x, y) with the given radius.
+func (r *MetaballRenderer) draw(d particleDrawer, x, y, radius float32, cr, cg, cb, ca uint8) {
+	cr, cg, cb, ca = metaballTint(cr, cg, cb, ca)
+	d.Sprite(r.sheet, 0, x, y, radius*r.config.Spread, 0, cr, cg, cb, ca)
+}
+
+// End finishes the density texture. Queued particles must be flushed
+// before.
+func (r *MetaballRenderer) End() {
+	rl.EndMode2D()
+	rl.EndBlendMode()
+	rl.EndTextureMode()
+}
+
+// Composite draws the blobs over the current target, stretched to w×h.
+func (r *MetaballRenderer) Composite(w, h float32) {
+	rl.BeginShaderMode(r.shader)
+	rl.SetShaderValue(r.shader, r.thresholdLoc, []float32{r.config.Threshold * metaballDensityScale}, rl.ShaderUniformFloat)
+	rl.SetShaderValue(r.shader, r.edgeLoc, []float32{r.config.Edge * metaballDensityScale}, rl.ShaderUniformFloat)
+	drawTarget(r.density, w, h)
+	rl.EndShaderMode()
+}
+
+// SetMetaballs sets the metaball configuration; nil draws particles as
+// circles again.
+func (r *MetaballRenderer) SetMetaballs(config *premium.Metaballs) {
+	r.config = config
+}
+
+// Metaballs returns the metaball configuration, nil if metaballs are off.
+func (r *MetaballRenderer) Metaballs() *premium.Metaballs {
+	return r.config
+}
+
+// Resize sets the screen size, recreating the density texture if it is
+// loaded.
+func (r *MetaballRenderer) Resize(width, height int32) {
+	if width == r.width && height == r.height {
+		return
+	}
+	r.width, r.height = width, height
+	if r.loaded {
+		rl.UnloadRenderTexture(r.density)
+		r.loadTarget()
+	}
+}
+
+// isMetaball reports whether an entity of the particles layer is drawn as
+// a metaball: every particle that is not drawn as a sprite.
+func (s *renderSystem) isMetaball(e *ecs.Entity) bool {
+	if e.Masked&components.MaskSprite == 0 {
+		return true
+	}
+	_, ok := s.sprites[e.Get(components.MaskSprite).(*components.Sprite).Sheet]
+	return !ok
+}
+
+// drawMetaballs draws the density of the metaball particles offset by the
+// screen shake. It runs before the scene is drawn, once the particles
+// layer is bucketed.
+func (s *renderSystem) drawMetaballs(camera rl.Camera2D, shakeX, shakeY float32) {
+	s.metaballs.Begin(camera)
+	d := s.drawer()
+	for _, e := range s.buckets[components.LayerParticles] {
+		if !s.isMetaball(e) {
+			continue
+		}
+		pos := e.Get(components.MaskPosition).(*components.Position)
+		col := e.Get(components.MaskColor).(*components.Color)
+		size := e.Get(components.MaskSize).(*components.Size)
+		s.metaballs.draw(d, pos.X+shakeX, pos.Y+shakeY, size.Radius, col.R, col.G, col.B, col.A)
+	}
+	if st := s.store; st != nil {
+		for i := range st.X {
+			c := st.Color[i]
+			s.metaballs.draw(d, st.X[i]+shakeX, st.Y[i]+shakeY, st.Radius[i], c[0], c[1], c[2], c[3])
+		}
+	}
+	s.flush()
+	s.metaballs.End()
+}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/deltatree/showcase/components"
 	"github.com/deltatree/showcase/premium"
-	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 // MotionBlurRenderer provides motion blur rendering for fast-moving particles.
@@ -32,21 +31,27 @@ func (r *MotionBlurRenderer) RenderWithBlur(
 	size *components.Size,
 	shakeX, shakeY float32,
 ) {
-	drawX := int32(pos.X + shakeX)
-	drawY := int32(pos.Y + shakeY)
-	drawColor := rl.NewColor(col.R, col.G, col.B, col.A)
+	var vx, vy float32
+	if vel != nil {
+		vx, vy = vel.X, vel.Y
+	}
+	r.draw(&immediate, pos.X+shakeX, pos.Y+shakeY, vx, vy, size.Radius, col.R, col.G, col.B, col.A)
+}
 
-	if !r.enabled || vel == nil {
-		rl.DrawCircle(drawX, drawY, size.Radius, drawColor)
+// draw draws a particle moving with velocity (vx, vy) at screen position
+// (x, y) with d, preceded by its blur samples.
+func (r *MotionBlurRenderer) draw(d particleDrawer, x, y, vx, vy, radius float32, cr, cg, cb, ca uint8) {
+	if !r.enabled {
+		d.Circle(x, y, radius, cr, cg, cb, ca)
 		return
 	}
 
 	// Calculate speed
-	speed := float32(math.Sqrt(float64(vel.X*vel.X + vel.Y*vel.Y)))
+	speed := float32(math.Sqrt(float64(vx*vx + vy*vy)))
 
 	if speed < r.threshold {
 		// Normal rendering for slow particles
-		rl.DrawCircle(drawX, drawY, size.Radius, drawColor)
+		d.Circle(x, y, radius, cr, cg, cb, ca)
 		return
 	}
 
@@ -57,7 +62,7 @@ func (r *MotionBlurRenderer) RenderWithBlur(
 	}
 
 	// Calculate step alpha
-	stepAlpha := float32(col.A) / float32(blurSteps+1)
+	stepAlpha := float32(ca) / float32(blurSteps+1)
 
 	// Time factor (~1 frame at 60fps)
 	dt := float32(0.016)
@@ -65,18 +70,17 @@ func (r *MotionBlurRenderer) RenderWithBlur(
 	for i := blurSteps; i >= 1; i-- {
 		t := float32(i) / float32(blurSteps)
 		// Position back in time
-		x := pos.X - vel.X*t*dt + shakeX
-		y := pos.Y - vel.Y*t*dt + shakeY
+		bx := x - vx*t*dt
+		by := y - vy*t*dt
 		// Fade and shrink
 		alpha := uint8(stepAlpha * (1.0 - t*0.5))
-		trailSize := size.Radius * (1.0 - t*0.3)
+		trailSize := radius * (1.0 - t*0.3)
 
-		blurColor := rl.NewColor(col.R, col.G, col.B, alpha)
-		rl.DrawCircle(int32(x), int32(y), trailSize, blurColor)
+		d.Circle(bx, by, trailSize, cr, cg, cb, alpha)
 	}
 
 	// Main particle on top
-	rl.DrawCircle(drawX, drawY, size.Radius, drawColor)
+	d.Circle(x, y, radius, cr, cg, cb, ca)
 }
 
 // SetEnabled enables or disables motion blur.
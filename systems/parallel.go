@@ -0,0 +1,100 @@
+package systems
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/andygeiss/ecs"
+)
+
+// minParallelChunk is the smallest number of particles worth handing to a
+// worker; below twice this size the work runs on the calling goroutine.
+const minParallelChunk = 512
+
+// WorkerPool splits per-particle loops into contiguous chunks processed by
+// a fixed set of goroutines.
+//
+// GravitySystem, PhysicsSystem and ColorSystem use a pool attached with
+// SetParallel. Each chunk only writes the components of its own particles,
+// so no locking is needed.
+//
+// A nil *WorkerPool is valid and runs everything sequentially.
+type WorkerPool struct {
+	workers int
+	tasks   chan func()
+}
+
+// NewWorkerPool starts a pool with the given number of workers. Values
+// below 1 use runtime.GOMAXPROCS(0).
+func NewWorkerPool(workers int) *WorkerPool {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	p := &WorkerPool{
+		workers: workers,
+		tasks:   make(chan func()),
+	}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for task := range p.tasks {
+				task()
+			}
+		}()
+	}
+	return p
+}
+
+// Workers returns the number of worker goroutines (1 for a nil pool).
+func (p *WorkerPool) Workers() int {
+	if p == nil {
+		return 1
+	}
+	return p.workers
+}
+
+// For calls fn with contiguous sub-ranges [lo, hi) covering [0, n) and
+// returns once all of them are done.
+func (p *WorkerPool) For(n int, fn func(lo, hi int)) {
+	if p == nil || p.workers < 2 || n < 2*minParallelChunk {
+		fn(0, n)
+		return
+	}
+
+	chunks := min(p.workers, n/minParallelChunk)
+	size := (n + chunks - 1) / chunks
+
+	var wg sync.WaitGroup
+	for lo := 0; lo < n; lo += size {
+		hi := min(lo+size, n)
+		wg.Add(1)
+		p.tasks <- func() {
+			defer wg.Done()
+			fn(lo, hi)
+		}
+	}
+	wg.Wait()
+}
+
+// Close stops the workers. The pool must not be used afterwards.
+func (p *WorkerPool) Close() {
+	if p != nil {
+		close(p.tasks)
+	}
+}
+
+// parallelUser is implemented by systems that can spread their work over
+// a WorkerPool.
+type parallelUser interface {
+	SetParallel(pool *WorkerPool)
+}
+
+// AttachWorkers attaches pool to every system that supports parallel
+// processing and ignores the rest, so the whole system list can be passed
+// in.
+func AttachWorkers(pool *WorkerPool, systems ...ecs.System) {
+	for _, sys := range systems {
+		if u, ok := sys.(parallelUser); ok {
+			u.SetParallel(pool)
+		}
+	}
+}
@@ -9,10 +9,12 @@
 //  1. InputSystem - handles mouse/keyboard input
 //  2. EmitterSystem - spawns new particles
 //  3. GravitySystem - applies attractor forces
-//  4. PhysicsSystem - updates positions and velocities
-//  5. LifetimeSystem - ages and removes expired entities
-//  6. ColorSystem - interpolates colors and sizes
-//  7. RenderSystem - draws entities to screen
+//  4. TargetSystem - steers particles towards morph targets
+//  5. PhysicsSystem - updates positions and velocities
+//  6. LifetimeSystem - ages and removes expired entities
+//  7. CommandFlushSystem - applies deferred entity changes
+//  8. ColorSystem - interpolates colors and sizes
+//  9. RenderSystem - draws entities to screen
 //
 // # Creating Custom Systems
 //
@@ -25,6 +27,8 @@
 package systems
 
 import (
+	"math"
+
 	"github.com/andygeiss/ecs"
 	"github.com/deltatree/showcase/components"
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -36,11 +40,22 @@ import (
 //
 // When entities move off-screen, they wrap around to the opposite edge,
 // creating a toroidal topology.
+//
+// Entities with SpeedOverLife move by their velocity scaled by the curve.
+// Entities with a Trail record their position before each step; the trail
+// is cleared when the entity wraps around an edge. Entities with Rotation
+// and AngularVelocity are spun.
+//
+// If a ParticleStore is attached with UseStore, its particles are
+// integrated as well. With a WorkerPool attached via SetParallel the
+// particles are processed in parallel chunks.
 type physicsSystem struct {
 	damping     float32
 	maxVelocity float32
 	width       float32
 	height      float32
+	store       *ParticleStore
+	workers     *WorkerPool
 }
 
 // NewPhysicsSystem creates a new physics system with configurable parameters.
@@ -49,7 +64,7 @@ type physicsSystem struct {
 //   - damping: velocity multiplier per frame (0.99 = 1% friction)
 //   - maxVelocity: maximum speed in pixels per second
 //   - width, height: screen dimensions for edge wrapping
-func NewPhysicsSystem(damping, maxVelocity, width, height float32) ecs.System {
+func NewPhysicsSystem(damping, maxVelocity, width, height float32) *physicsSystem {
 	return &physicsSystem{
 		damping:     damping,
 		maxVelocity: maxVelocity,
@@ -61,47 +76,129 @@ func NewPhysicsSystem(damping, maxVelocity, width, height float32) ecs.System {
 func (s *physicsSystem) Setup() {}
 
 func (s *physicsSystem) Process(em ecs.EntityManager) (state int) {
-	dt := rl.GetFrameTime()
+	s.update(em, rl.GetFrameTime())
+	return ecs.StateEngineContinue
+}
 
-	entities := em.FilterByMask(components.MaskPosition | components.MaskVelocity)
+// update integrates all entities and store particles by dt seconds.
+func (s *physicsSystem) update(em ecs.EntityManager, dt float32) {
+	entities := query(em, components.MaskPosition|components.MaskVelocity)
 
-	for _, e := range entities {
-		pos := e.Get(components.MaskPosition).(*components.Position)
-		vel := e.Get(components.MaskVelocity).(*components.Velocity)
+	s.workers.For(len(entities), func(lo, hi int) {
+		for _, e := range entities[lo:hi] {
+			pos := e.Get(components.MaskPosition).(*components.Position)
+			vel := e.Get(components.MaskVelocity).(*components.Velocity)
 
-		if acc := e.Get(components.MaskAcceleration); acc != nil {
-			a := acc.(*components.Acceleration)
-			vel.X += a.X * dt
-			vel.Y += a.Y * dt
-		}
+			var ax, ay float32
+			if acc := e.Get(components.MaskAcceleration); acc != nil {
+				a := acc.(*components.Acceleration)
+				ax, ay = a.X, a.Y
+			}
 
-		vel.X *= s.damping
-		vel.Y *= s.damping
+			speed := float32(1)
+			if e.Masked&components.MaskSpeedOverLife != 0 && e.Masked&components.MaskLifetime != 0 {
+				sol := e.Get(components.MaskSpeedOverLife).(*components.SpeedOverLife)
+				life := e.Get(components.MaskLifetime).(*components.Lifetime)
+				speed = sol.Curve.At(life.Progress())
+			}
 
-		mag := vel.Magnitude()
-		if mag > s.maxVelocity {
-			vel.X = vel.X / mag * s.maxVelocity
-			vel.Y = vel.Y / mag * s.maxVelocity
-		}
+			x, y := pos.X, pos.Y
+			pos.X, pos.Y, vel.X, vel.Y = s.step(x, y, vel.X, vel.Y, ax, ay, speed, dt)
 
-		pos.X += vel.X * dt
-		pos.Y += vel.Y * dt
+			if e.Masked&components.MaskTrail != 0 {
+				trail := e.Get(components.MaskTrail).(*components.Trail)
+				if s.wrapped(x, y, pos.X, pos.Y) {
+					trail.Reset()
+				} else {
+					trail.Push(x, y)
+				}
+			}
 
-		if pos.X < 0 {
-			pos.X = s.width
-		}
-		if pos.X > s.width {
-			pos.X = 0
-		}
-		if pos.Y < 0 {
-			pos.Y = s.height
-		}
-		if pos.Y > s.height {
-			pos.Y = 0
+			if e.Masked&maskSpin == maskSpin {
+				rot := e.Get(components.MaskRotation).(*components.Rotation)
+				av := e.Get(components.MaskAngularVelocity).(*components.AngularVelocity)
+				rot.Angle = wrapDegrees(rot.Angle + av.Speed*dt)
+			}
 		}
+	})
+
+	if st := s.store; st != nil {
+		s.workers.For(st.Len(), func(lo, hi int) {
+			for i := lo; i < hi; i++ {
+				st.X[i], st.Y[i], st.VX[i], st.VY[i] = s.step(st.X[i], st.Y[i], st.VX[i], st.VY[i], st.AX[i], st.AY[i], 1, dt)
+			}
+		})
 	}
+}
 
-	return ecs.StateEngineContinue
+// step advances a single particle: it integrates acceleration, applies
+// damping and the velocity limit, moves the position by the velocity
+// scaled by speed and wraps it around the screen edges.
+func (s *physicsSystem) step(x, y, vx, vy, ax, ay, speed, dt float32) (float32, float32, float32, float32) {
+	vx += ax * dt
+	vy += ay * dt
+
+	vx *= s.damping
+	vy *= s.damping
+
+	mag := float32(math.Sqrt(float64(vx*vx + vy*vy)))
+	if mag > s.maxVelocity {
+		vx = vx / mag * s.maxVelocity
+		vy = vy / mag * s.maxVelocity
+	}
+
+	x += vx * speed * dt
+	y += vy * speed * dt
+
+	if x < 0 {
+		x = s.width
+	}
+	if x > s.width {
+		x = 0
+	}
+	if y < 0 {
+		y = s.height
+	}
+	if y > s.height {
+		y = 0
+	}
+	return x, y, vx, vy
+}
+
+// maskSpin selects entities PhysicsSystem rotates.
+const maskSpin = components.MaskRotation | components.MaskAngularVelocity
+
+// wrapDegrees maps an angle to [0, 360).
+func wrapDegrees(deg float32) float32 {
+	deg = float32(math.Mod(float64(deg), 360))
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
+
+// wrapped reports whether a move from (x0, y0) to (x1, y1) crossed a
+// screen edge.
+func (s *physicsSystem) wrapped(x0, y0, x1, y1 float32) bool {
+	dx, dy := x1-x0, y1-y0
+	return dx > s.width/2 || dx < -s.width/2 || dy > s.height/2 || dy < -s.height/2
+}
+
+// UseStore makes the system integrate the particles of store.
+func (s *physicsSystem) UseStore(store *ParticleStore) {
+	s.store = store
+}
+
+// SetBounds sets the world size particles wrap around, e.g. after the
+// window was resized.
+func (s *physicsSystem) SetBounds(width, height float32) {
+	s.width = width
+	s.height = height
+}
+
+// SetParallel spreads the work over pool (nil runs sequentially).
+func (s *physicsSystem) SetParallel(pool *WorkerPool) {
+	s.workers = pool
 }
 
 func (s *physicsSystem) Teardown() {}
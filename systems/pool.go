@@ -0,0 +1,180 @@
+package systems
+
+import (
+	"github.com/andygeiss/ecs"
+	"github.com/deltatree/showcase/components"
+)
+
+// particleLayout is the number of leading components every recyclable
+// particle entity has, in this order: Position, Velocity, Acceleration,
+// Color, Lifetime, Size, Particle. EmitterSystem and all presets build
+// particles with this layout.
+const particleLayout = 7
+
+// PooledParticle gives typed access to the components of a particle entity
+// handed out by ParticlePool, so callers can initialize it without type
+// assertions.
+type PooledParticle struct {
+	Entity       *ecs.Entity
+	Position     *components.Position
+	Velocity     *components.Velocity
+	Acceleration *components.Acceleration
+	Color        *components.Color
+	Lifetime     *components.Lifetime
+	Size         *components.Size
+}
+
+// ParticlePool recycles expired particle entities together with their
+// components. Spawning a particle from the pool costs no allocations once
+// the pool has warmed up, which removes most of the GC churn caused by
+// spawning and expiring hundreds of particles per second.
+//
+// EmitterSystem acquires particles from its pool; LifetimeSystem returns
+// expired particles to the pool when one is attached with SetPool.
+type ParticlePool struct {
+	free      []*ecs.Entity
+	pooled    map[*ecs.Entity]bool // Entities in free
+	spares    map[uint64][]ecs.Component
+	allocated int
+}
+
+// NewParticlePool creates an empty particle pool.
+func NewParticlePool() *ParticlePool {
+	return &ParticlePool{}
+}
+
+// Acquire returns a particle entity ready to be added to the entity
+// manager. Recycled entities keep their ID but have their acceleration
+// and lifetime reset; all other values must be set by the caller.
+func (p *ParticlePool) Acquire() PooledParticle {
+	var e *ecs.Entity
+	if n := len(p.free); n > 0 {
+		e = p.free[n-1]
+		p.free[n-1] = nil
+		p.free = p.free[:n-1]
+		delete(p.pooled, e)
+	} else {
+		e = ecs.NewEntity(components.NewParticleID(), []ecs.Component{
+			components.NewPosition(),
+			components.NewVelocity(),
+			components.NewAcceleration(),
+			components.NewColor(),
+			components.NewLifetime(),
+			components.NewSize(),
+			components.NewParticle(),
+		})
+		p.allocated++
+	}
+
+	pp := PooledParticle{
+		Entity:       e,
+		Position:     e.Components[0].(*components.Position),
+		Velocity:     e.Components[1].(*components.Velocity),
+		Acceleration: e.Components[2].(*components.Acceleration),
+		Color:        e.Components[3].(*components.Color),
+		Lifetime:     e.Components[4].(*components.Lifetime),
+		Size:         e.Components[5].(*components.Size),
+	}
+	pp.Acceleration.Reset()
+	pp.Lifetime.Age = 0
+	pp.Lifetime.Expired = false
+	return pp
+}
+
+// Release returns an entity that has been removed from the entity manager
+// to the pool. Components added after spawning (e.g. Target) are dropped;
+// per-particle state (Trail, Rotation, AngularVelocity) is kept for
+// AcquireTrail and AcquireSpin.
+// Entities that do not have the particle layout or are already in the
+// pool are ignored; Release reports whether the entity was recycled.
+func (p *ParticlePool) Release(e *ecs.Entity) bool {
+	if p.pooled[e] || !isParticleLayout(e) {
+		return false
+	}
+	for i := particleLayout; i < len(e.Components); i++ {
+		switch c := e.Components[i].(type) {
+		case *components.Trail, *components.Rotation, *components.AngularVelocity:
+			if p.spares == nil {
+				p.spares = make(map[uint64][]ecs.Component)
+			}
+			p.spares[c.Mask()] = append(p.spares[c.Mask()], c)
+		}
+		e.Components[i] = nil
+	}
+	e.Components = e.Components[:particleLayout]
+	e.Masked = components.MaskFullParticle
+	if e.Id == "" {
+		e.Id = components.NewParticleID()
+	}
+	if p.pooled == nil {
+		p.pooled = make(map[*ecs.Entity]bool)
+	}
+	p.pooled[e] = true
+	p.free = append(p.free, e)
+	return true
+}
+
+// AcquireTrail returns an empty trail of the given length, reusing the
+// trail of a released particle when one is available.
+func (p *ParticlePool) AcquireTrail(length int) *components.Trail {
+	if c := p.spare(components.MaskTrail); c != nil {
+		trail := c.(*components.Trail)
+		trail.Resize(length)
+		return trail
+	}
+	return components.NewTrail(length)
+}
+
+// AcquireSpin returns a Rotation at angle and an AngularVelocity of speed,
+// reusing the components of released particles when available.
+func (p *ParticlePool) AcquireSpin(angle, speed float32) (*components.Rotation, *components.AngularVelocity) {
+	rot, _ := p.spare(components.MaskRotation).(*components.Rotation)
+	if rot == nil {
+		rot = components.NewRotation()
+	}
+	av, _ := p.spare(components.MaskAngularVelocity).(*components.AngularVelocity)
+	if av == nil {
+		av = components.NewAngularVelocity()
+	}
+	return rot.WithAngle(angle), av.WithSpeed(speed)
+}
+
+// spare pops a released component with the given mask, or returns nil.
+func (p *ParticlePool) spare(mask uint64) ecs.Component {
+	list := p.spares[mask]
+	n := len(list)
+	if n == 0 {
+		return nil
+	}
+	c := list[n-1]
+	list[n-1] = nil
+	p.spares[mask] = list[:n-1]
+	return c
+}
+
+// Free returns the number of entities waiting to be reused.
+func (p *ParticlePool) Free() int {
+	return len(p.free)
+}
+
+// Allocated returns the number of entities the pool has created.
+func (p *ParticlePool) Allocated() int {
+	return p.allocated
+}
+
+// isParticleLayout reports whether e starts with the seven particle
+// components in pool order.
+func isParticleLayout(e *ecs.Entity) bool {
+	if len(e.Components) < particleLayout {
+		return false
+	}
+	c := e.Components
+	_, ok0 := c[0].(*components.Position)
+	_, ok1 := c[1].(*components.Velocity)
+	_, ok2 := c[2].(*components.Acceleration)
+	_, ok3 := c[3].(*components.Color)
+	_, ok4 := c[4].(*components.Lifetime)
+	_, ok5 := c[5].(*components.Size)
+	_, ok6 := c[6].(*components.Particle)
+	return ok0 && ok1 && ok2 && ok3 && ok4 && ok5 && ok6
+}
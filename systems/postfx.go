@@ -0,0 +1,275 @@
+package systems
+
+import (
+	"image/color"
+	"slices"
+
+	"github.com/deltatree/showcase/premium"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Post-process shaders use raylib's default vertex shader, like the bloom
+// shaders. Every shader has the uniforms strength and shape (see
+// premium.PostEffect), resolution (the screen size in pixels) and time (in
+// seconds), and replaces the pixels it draws.
+
+// vignetteShader darkens the screen towards the corners.
+const vignetteShader = `#version 330
+in vec2 fragTexCoord;
+in vec4 fragColor;
+uniform sampler2D texture0;
+uniform float strength;
+uniform float shape;
+out vec4 finalColor;
+
+void main() {
+	vec3 c = texture(texture0, fragTexCoord).rgb;
+	// 0 in the center, 1 in the corners
+	float d = length(fragTexCoord - 0.5) * 1.41421356;
+	float k = smoothstep(shape, 1.0, d);
+	finalColor = vec4(c * (1.0 - strength * k), 1.0);
+}
+`
+
+// aberrationShader samples red and blue further out and further in than
+// green, growing towards the edges.
+const aberrationShader = `#version 330
+in vec2 fragTexCoord;
+in vec4 fragColor;
+uniform sampler2D texture0;
+uniform float strength;
+uniform vec2 resolution;
+out vec4 finalColor;
+
+void main() {
+	vec2 offset = (fragTexCoord - 0.5) * 2.0 * strength / resolution;
+	float r = texture(texture0, fragTexCoord + offset).r;
+	float g = texture(texture0, fragTexCoord).g;
+	float b = texture(texture0, fragTexCoord - offset).b;
+	finalColor = vec4(r, g, b, 1.0);
+}
+`
+
+// grainShader adds per-pixel noise that changes every frame.
+const grainShader = `#version 330
+in vec2 fragTexCoord;
+in vec4 fragColor;
+uniform sampler2D texture0;
+uniform float strength;
+uniform float time;
+out vec4 finalColor;
+
+void main() {
+	vec3 c = texture(texture0, fragTexCoord).rgb;
+	float n = fract(sin(dot(gl_FragCoord.xy + fract(time) * 97.0, vec2(12.9898, 78.233))) * 43758.5453);
+	finalColor = vec4(c + (n - 0.5) * strength, 1.0);
+}
+`
+
+// crtShader curves the picture like a tube screen, blacking out what falls
+// off the tube, and darkens every other line.
+const crtShader = `#version 330
+in vec2 fragTexCoord;
+in vec4 fragColor;
+uniform sampler2D texture0;
+uniform float strength;
+uniform float shape;
+out vec4 finalColor;
+
+void main() {
+	vec2 cc = fragTexCoord * 2.0 - 1.0;
+	cc *= 1.0 + shape * dot(cc, cc) * 0.5;
+	vec2 uv = cc * 0.5 + 0.5;
+	if (uv.x < 0.0 || uv.x > 1.0 || uv.y < 0.0 || uv.y > 1.0) {
+		finalColor = vec4(0.0, 0.0, 0.0, 1.0);
+		return;
+	}
+	vec3 c = texture(texture0, uv).rgb;
+	// Pixel rows alternate between sin = 1 and sin = -1
+	float line = 0.5 - 0.5 * sin(gl_FragCoord.y * 3.14159265);
+	finalColor = vec4(c * (1.0 - strength * line), 1.0);
+}
+`
+
+// postEffectShaders holds the shader source of each effect kind.
+var postEffectShaders = [premium.PostEffectKinds]string{
+	premium.EffectVignette:            vignetteShader,
+	premium.EffectChromaticAberration: aberrationShader,
+	premium.EffectFilmGrain:           grainShader,
+	premium.EffectCRT:                 crtShader,
+}
+
+// postShader is a compiled effect shader and its uniform locations.
+type postShader struct {
+	shader                                        rl.Shader
+	strengthLoc, shapeLoc, resolutionLoc, timeLoc int32
+}
+
+// PostFXRenderer applies a stack of full-screen effects (premium.PostEffect)
+// to the final frame. The frame is drawn into an offscreen render texture;
+// each effect then draws the output of the previous one through its
+// shader, ping-ponging between two textures, and the last one draws to
+// the screen.
+//
+// The stack is set per preset by SetEffects and runs only while enabled by
+// the quality level.
+//
+// GPU resources are created by Load once the window exists and released
+// by Unload.
+type PostFXRenderer struct {
+	enabled bool
+	effects []premium.PostEffect
+
+	width, height int32
+	loaded        bool
+
+	ping, pong rl.RenderTexture2D
+	shaders    [premium.PostEffectKinds]postShader
+}
+
+// NewPostFXRenderer creates a post-process renderer for a screen of the
+// given size, without effects.
+func NewPostFXRenderer(width, height int32) *PostFXRenderer {
+	return &PostFXRenderer{width: width, height: height}
+}
+
+// Load compiles the shaders and creates the render textures. It must be
+// called after the window has been created. If a shader cannot be
+// compiled, post-processing stays inactive.
+func (r *PostFXRenderer) Load() {
+	if r.loaded {
+		return
+	}
+	for kind, source := range postEffectShaders {
+		shader := rl.LoadShaderFromMemory("", source)
+		r.shaders[kind] = postShader{
+			shader:        shader,
+			strengthLoc:   rl.GetShaderLocation(shader, "strength"),
+			shapeLoc:      rl.GetShaderLocation(shader, "shape"),
+			resolutionLoc: rl.GetShaderLocation(shader, "resolution"),
+			timeLoc:       rl.GetShaderLocation(shader, "time"),
+		}
+		if !rl.IsShaderValid(shader) {
+			r.unloadShaders()
+			return
+		}
+	}
+	r.loadTargets()
+	r.loaded = true
+}
+
+func (r *PostFXRenderer) loadTargets() {
+	r.ping = rl.LoadRenderTexture(r.width, r.height)
+	r.pong = rl.LoadRenderTexture(r.width, r.height)
+}
+
+func (r *PostFXRenderer) unloadTargets() {
+	rl.UnloadRenderTexture(r.ping)
+	rl.UnloadRenderTexture(r.pong)
+}
+
+func (r *PostFXRenderer) unloadShaders() {
+	for kind := range r.shaders {
+		if rl.IsShaderValid(r.shaders[kind].shader) {
+			rl.UnloadShader(r.shaders[kind].shader)
+		}
+		r.shaders[kind] = postShader{}
+	}
+}
+
+// Unload releases the GPU resources.
+func (r *PostFXRenderer) Unload() {
+	if !r.loaded {
+		return
+	}
+	r.unloadTargets()
+	r.unloadShaders()
+	r.loaded = false
+}
+
+// Active reports whether the next frame is post-processed.
+func (r *PostFXRenderer) Active() bool {
+	return r.enabled && r.loaded && len(r.effects) > 0
+}
+
+// Begin redirects drawing into the input texture and clears it with
+// background. It reports whether post-processing is active; if not,
+// drawing goes to the screen as usual and End must not be called.
+func (r *PostFXRenderer) Begin(background color.RGBA) bool {
+	if !r.Active() {
+		return false
+	}
+	rl.BeginTextureMode(r.ping)
+	rl.ClearBackground(background)
+	return true
+}
+
+// End finishes the frame and draws it to the screen through the effect
+// stack; t is the time in seconds for animated effects.
+func (r *PostFXRenderer) End(t float32) {
+	rl.EndTextureMode()
+
+	w, h := float32(r.width), float32(r.height)
+	src, dst := r.ping, r.pong
+	for i, e := range r.effects {
+		last := i == len(r.effects)-1
+		if !last {
+			rl.BeginTextureMode(dst)
+		}
+		s := r.shaders[e.Kind]
+		rl.BeginShaderMode(s.shader)
+		rl.SetShaderValue(s.shader, s.strengthLoc, []float32{e.Strength}, rl.ShaderUniformFloat)
+		rl.SetShaderValue(s.shader, s.shapeLoc, []float32{e.Shape}, rl.ShaderUniformFloat)
+		rl.SetShaderValue(s.shader, s.resolutionLoc, []float32{w, h}, rl.ShaderUniformVec2)
+		rl.SetShaderValue(s.shader, s.timeLoc, []float32{t}, rl.ShaderUniformFloat)
+		// Effects write opaque pixels, so the input is not blended with
+		// what the target held before
+		copyTarget(src, w, h)
+		rl.EndShaderMode()
+		if !last {
+			rl.EndTextureMode()
+			src, dst = dst, src
+		}
+	}
+}
+
+// SetEffects sets the effect stack, applied in order. Effects of unknown
+// kinds are dropped.
+func (r *PostFXRenderer) SetEffects(effects []premium.PostEffect) {
+	r.effects = slices.DeleteFunc(slices.Clone(effects), func(e premium.PostEffect) bool {
+		return e.Kind < 0 || e.Kind >= premium.PostEffectKinds
+	})
+}
+
+// Effects returns the effect stack.
+func (r *PostFXRenderer) Effects() []premium.PostEffect {
+	return slices.Clone(r.effects)
+}
+
+// Resize sets the screen size, recreating the render textures if they
+// are loaded.
+func (r *PostFXRenderer) Resize(width, height int32) {
+	if width == r.width && height == r.height {
+		return
+	}
+	r.width, r.height = width, height
+	if r.loaded {
+		r.unloadTargets()
+		r.loadTargets()
+	}
+}
+
+// SetEnabled enables or disables post-processing.
+func (r *PostFXRenderer) SetEnabled(enabled bool) {
+	r.enabled = enabled
+}
+
+// IsEnabled returns whether post-processing is enabled.
+func (r *PostFXRenderer) IsEnabled() bool {
+	return r.enabled
+}
+
+// ApplyQuality applies quality settings to post-processing.
+func (r *PostFXRenderer) ApplyQuality(q premium.QualitySettings) {
+	r.enabled = q.PostFX
+}
@@ -0,0 +1,210 @@
+package systems
+
+import (
+	"github.com/andygeiss/ecs"
+	"github.com/deltatree/showcase/components"
+)
+
+// QueryCache is an ecs.EntityManager that keeps one entity list per queried
+// mask and updates those lists incrementally on Add and Remove, instead of
+// scanning every entity on each FilterByMask call.
+//
+// It is a drop-in replacement for ecs.NewEntityManager. Systems in this
+// package detect it and iterate the cached lists directly; FilterByMask
+// still returns a copy so existing callers may add and remove entities
+// while iterating the result.
+//
+// Lists are created on the first query for a mask and maintained from
+// then on. Entities whose components change after they were added must be
+// re-indexed with Refresh.
+//
+// Removing an entity moves the last entity of each list into its slot, so
+// list order is not insertion order.
+type QueryCache struct {
+	all     entityList
+	queries map[uint64]*entityList
+	masks   map[*ecs.Entity]uint64 // mask each entity is indexed under
+}
+
+// NewQueryCache creates an empty query cache.
+func NewQueryCache() *QueryCache {
+	return &QueryCache{
+		all:     newEntityList(),
+		queries: make(map[uint64]*entityList),
+		masks:   make(map[*ecs.Entity]uint64),
+	}
+}
+
+// Add adds entities and indexes them in every cached query.
+func (c *QueryCache) Add(entities ...*ecs.Entity) {
+	for _, e := range entities {
+		if !c.all.add(e) {
+			continue
+		}
+		c.masks[e] = e.Masked
+		for mask, q := range c.queries {
+			if e.Masked&mask == mask {
+				q.add(e)
+			}
+		}
+	}
+}
+
+// Entities returns all entities. The slice is owned by the cache.
+func (c *QueryCache) Entities() []*ecs.Entity {
+	return c.all.entities
+}
+
+// FilterByMask returns a copy of the entities whose components match mask.
+func (c *QueryCache) FilterByMask(mask uint64) []*ecs.Entity {
+	q := c.Query(mask)
+	out := make([]*ecs.Entity, len(q))
+	copy(out, q)
+	return out
+}
+
+// FilterByNames returns the entities having components with all of the
+// given names. Name queries are not cached.
+func (c *QueryCache) FilterByNames(names ...string) []*ecs.Entity {
+	var out []*ecs.Entity
+	for _, e := range c.all.entities {
+		matched := 0
+		for _, name := range names {
+			for _, comp := range e.Components {
+				if n, ok := comp.(ecs.ComponentWithName); ok && n.Name() == name {
+					matched++
+				}
+			}
+		}
+		if matched == len(names) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Get returns the first entity with the given Id, or nil.
+func (c *QueryCache) Get(id string) *ecs.Entity {
+	for _, e := range c.all.entities {
+		if e.Id == id {
+			return e
+		}
+	}
+	return nil
+}
+
+// Remove removes an entity from the cache and all queries. Entities are
+// matched by identity; an entity not held by the cache is matched by Id,
+// like the default entity manager does.
+func (c *QueryCache) Remove(entity *ecs.Entity) {
+	if _, ok := c.all.index[entity]; !ok {
+		entity = c.Get(entity.Id)
+		if entity == nil {
+			return
+		}
+	}
+	c.all.remove(entity)
+	for _, q := range c.queries {
+		q.remove(entity)
+	}
+	delete(c.masks, entity)
+}
+
+// Query returns the live list of entities matching mask. The slice is
+// owned by the cache and changes on Add and Remove; callers must not
+// modify it or remove entities while iterating it.
+func (c *QueryCache) Query(mask uint64) []*ecs.Entity {
+	if q, ok := c.queries[mask]; ok {
+		return q.entities
+	}
+	q := newEntityList()
+	for _, e := range c.all.entities {
+		if e.Masked&mask == mask {
+			q.add(e)
+		}
+	}
+	c.queries[mask] = &q
+	return q.entities
+}
+
+// Count returns the number of entities matching mask. After the first
+// call for a mask it runs in constant time.
+func (c *QueryCache) Count(mask uint64) int {
+	return len(c.Query(mask))
+}
+
+// Refresh re-indexes an entity after components were added to or removed
+// from it.
+func (c *QueryCache) Refresh(e *ecs.Entity) {
+	old, ok := c.masks[e]
+	if !ok || old == e.Masked {
+		return
+	}
+	for mask, q := range c.queries {
+		was := old&mask == mask
+		is := e.Masked&mask == mask
+		switch {
+		case is && !was:
+			q.add(e)
+		case was && !is:
+			q.remove(e)
+		}
+	}
+	c.masks[e] = e.Masked
+}
+
+// entityList is a slice of entities with an index for O(1) removal.
+type entityList struct {
+	entities []*ecs.Entity
+	index    map[*ecs.Entity]int
+}
+
+func newEntityList() entityList {
+	return entityList{index: make(map[*ecs.Entity]int)}
+}
+
+// add appends e unless it is already present and reports whether it was
+// added.
+func (l *entityList) add(e *ecs.Entity) bool {
+	if _, ok := l.index[e]; ok {
+		return false
+	}
+	l.index[e] = len(l.entities)
+	l.entities = append(l.entities, e)
+	return true
+}
+
+// remove deletes e by moving the last entity into its slot.
+func (l *entityList) remove(e *ecs.Entity) {
+	i, ok := l.index[e]
+	if !ok {
+		return
+	}
+	last := len(l.entities) - 1
+	if i != last {
+		moved := l.entities[last]
+		l.entities[i] = moved
+		l.index[moved] = i
+	}
+	l.entities[last] = nil
+	l.entities = l.entities[:last]
+	delete(l.index, e)
+}
+
+// query returns the entities matching mask. With a QueryCache it returns
+// the cached live list without allocating; the caller must not remove
+// entities while iterating it.
+func query(em ecs.EntityManager, mask uint64) []*ecs.Entity {
+	if c, ok := em.(*QueryCache); ok {
+		return c.Query(mask)
+	}
+	return em.FilterByMask(mask)
+}
+
+// countParticles returns the number of particle entities.
+func countParticles(em ecs.EntityManager) int {
+	if c, ok := em.(*QueryCache); ok {
+		return c.Count(components.MaskParticle)
+	}
+	return len(em.FilterByMask(components.MaskParticle))
+}
@@ -9,21 +9,97 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+// RenderPass identifies one stage of particle drawing. Passes are
+// combined as a bit set.
+type RenderPass uint8
+
+// Particle render passes, drawn in this order.
+const (
+	// PassTrails draws the Trail of each entity underneath all particles.
+	PassTrails RenderPass = 1 << iota
+	// PassGlow draws palette-tinted glow layers behind each particle.
+	PassGlow
+	// PassMotionBlur draws blur samples behind fast particles.
+	PassMotionBlur
+	// PassBloom post-processes the particles with a GPU bloom. While
+	// bloom is active it replaces the glow pass.
+	PassBloom
+	// PassPostFX runs the preset's post-process effect stack over the
+	// final frame.
+	PassPostFX
+	// PassLighting lights the background with the particles as light
+	// sources.
+	PassLighting
+
+	// AllPasses enables every pass.
+	AllPasses = PassTrails | PassGlow | PassMotionBlur | PassBloom | PassPostFX | PassLighting
+)
+
 // RenderSystem handles window management and rendering of all visible entities.
 // It initializes the raylib window, handles window close events, and draws
 // particles as filled circles with their current color and size.
 //
+// The window is resizable. When its size changes, by resizing or by
+// toggling fullscreen, the HUD layout and bloom targets follow and the
+// callback set by SetOnResize updates the rest of the world.
+//
+// Particles are drawn in passes: trails (TrailRenderer), glow
+// (GlowRenderer) and the particle itself with motion blur
+// (MotionBlurRenderer), optionally post-processed with a GPU bloom
+// (BloomRenderer) instead of the glow. SetPasses selects the passes;
+// within them, the active quality level decides what is drawn (glow
+// layers, blur samples, trail length, bloom radius and resolution) and the
+// preset palette tints the glow.
+//
+// Entities with a Sprite component are drawn with a frame of a sprite
+// sheet registered by AddSpriteSheet, rotated by their Rotation.
+//
+// By default particles are batched (BatchRenderer): every circle and
+// sprite becomes a textured quad and each texture is drawn with a single
+// draw call. SetBatched(false) or the B key switch to one raylib call per
+// shape; the debug overlay shows the particle draw calls of either mode.
+//
+// Behind everything, BackgroundRenderer draws the background set by
+// SetBackground: a solid color, gradient, parallax starfield or image. A
+// persistent background fades the previous frame instead of clearing it,
+// leaving long-exposure light trails.
+//
+// With SetMetaballs, particles not drawn as sprites become liquid blobs
+// (MetaballRenderer) that merge with their neighbors.
+//
+// With SetLighting, LightRenderer lights the background and the
+// background layer with the particles as colored light sources.
+//
+// Last, PostFXRenderer runs the effect stack set by SetPostEffects
+// (vignette, chromatic aberration, film grain, CRT) over the finished
+// frame, unless the quality level turns it off.
+//
+// The world is drawn through the first Camera entity (see CameraSystem),
+// whose zoom pulses with ApplyPulse.
+//
+// Entities are drawn by render layer (background, particles, foreground,
+// UI), ordered by the Z of their Layer component within a layer; see
+// layers.go. Each layer has its own blend mode and can be hidden. The
+// particles layer is blended with the mode set by SetBlendMode (alpha
+// blending by default); entities with a Blend component are drawn after
+// the rest of their layer in their own mode. With gizmos on (G key),
+// attractors are drawn as rings in their layer, the foreground by default.
+//
 // Debug overlay (toggle with F3) displays:
 //   - FPS counter
 //   - Active entity count
 //   - Current preset name
+//   - Quality, draw calls and juice level
 //   - Mouse coordinates
 //   - Control hints
 type renderSystem struct {
 	width, height    int32
+	layout           *premium.UILayout // Anchors the HUD to the window edges
+	onResize         func(width, height int32)
 	title            string
 	showDebug        bool
 	presetName       string
+	juiceLevel       premium.JuiceLevel
 	quality          premium.QualitySettings
 	uiState          *premium.UIState
 	effects          *premium.ScreenEffects
@@ -31,6 +107,31 @@ type renderSystem struct {
 	maxParticles     int32     // For slider
 	onParticleChange func(int) // Callback when slider changes
 	isFullscreen     bool      // Track fullscreen state
+	store            *ParticleStore
+	passes           RenderPass
+	trails           *TrailRenderer
+	glow             *GlowRenderer
+	blur             *MotionBlurRenderer
+	bloom            *BloomRenderer
+	background       *BackgroundRenderer
+	postfx           *PostFXRenderer
+	metaballs        *MetaballRenderer
+	lights           *LightRenderer
+	metaballsActive  bool        // Particles are drawn as metaballs in the current frame
+	camera           rl.Camera2D // View of the current frame
+	bloomActive      bool        // Bloom replaces glow in the current frame
+	batch            *BatchRenderer
+	batched          bool            // Draw particles through batch
+	direct           immediateDrawer // Draws particles when not batched
+	particleDraws    int             // Particle draw calls of the last frame
+	layers           [components.LayerCount]layerState
+	buckets          [components.LayerCount][]*ecs.Entity // Renderable entities by layer
+	gizmos           [components.LayerCount][]*ecs.Entity // Attractors by layer
+	showGizmos       bool
+	drawMode         components.BlendMode       // Blend mode currently drawn with
+	blendQueues      [4][]*ecs.Entity           // Particles with a different Blend, by mode
+	sprites          map[string]*SpriteSheet    // Sprite sheets by name
+	onQualityChange  func(premium.QualityLevel) // Callback when quality changes
 }
 
 // NewRenderSystem creates a new render system for the specified window.
@@ -39,23 +140,56 @@ type renderSystem struct {
 //   - width, height: window dimensions in pixels
 //   - title: window title displayed in the title bar
 func NewRenderSystem(width, height int32, title string) *renderSystem {
-	return &renderSystem{
+	s := &renderSystem{
 		width:        width,
 		height:       height,
+		layout:       newHUDLayout(width, height),
 		title:        title,
 		showDebug:    true,
 		presetName:   "Fountain",
+		juiceLevel:   premium.JuiceNormal,
 		quality:      premium.GetQualitySettings(premium.QualityMedium), // Default to MEDIUM for better performance
 		uiState:      premium.NewUIState(),
 		effects:      premium.NewScreenEffects(),
 		palette:      premium.GalaxyPalette,
 		maxParticles: 10000,
+		passes:       AllPasses,
+		trails:       NewTrailRenderer(0, TrailLines),
+		glow:         NewGlowRenderer(false, 0),
+		blur:         NewMotionBlurRenderer(false, 0),
+		bloom:        NewBloomRenderer(width, height),
+		background:   NewBackgroundRenderer(width, height),
+		postfx:       NewPostFXRenderer(width, height),
+		metaballs:    NewMetaballRenderer(width, height),
+		lights:       NewLightRenderer(width, height),
+		batch:        NewBatchRenderer(),
+		batched:      true,
+		sprites:      make(map[string]*SpriteSheet),
 	}
+	for i := range s.layers {
+		s.layers[i].visible = true
+	}
+	s.applyQuality()
+	s.glow.SetPalette(s.palette)
+	return s
+}
+
+// newHUDLayout creates the layout of the debug overlay and controls hint.
+func newHUDLayout(width, height int32) *premium.UILayout {
+	l := premium.NewUILayout(int(width), int(height))
+	l.MarginX, l.MarginY = 10, 10
+	return l
 }
 
 func (s *renderSystem) Setup() {
+	rl.SetConfigFlags(rl.FlagWindowResizable)
 	rl.InitWindow(s.width, s.height, s.title)
+	rl.SetWindowMinSize(640, 360)
 	rl.SetTargetFPS(60)
+	s.bloom.Load()
+	s.postfx.Load()
+	s.metaballs.Load()
+	s.lights.Load()
 }
 
 func (s *renderSystem) Process(em ecs.EntityManager) (state int) {
@@ -79,9 +213,29 @@ func (s *renderSystem) Process(em ecs.EntityManager) (state int) {
 		rl.ToggleFullscreen()
 	}
 
+	// Resizing the window or toggling fullscreen changes the screen size
+	if w, h := int32(rl.GetScreenWidth()), int32(rl.GetScreenHeight()); w > 0 && h > 0 && (w != s.width || h != s.height) {
+		s.resize(w, h)
+	}
+
+	// Toggle particle trails with F4
+	if rl.IsKeyPressed(rl.KeyF4) {
+		s.trails.SetEnabled(!s.trails.IsEnabled() && s.trails.Length() > 0)
+	}
+
+	// Toggle batched rendering with B
+	if rl.IsKeyPressed(rl.KeyB) {
+		s.batched = !s.batched
+	}
+
+	// Toggle attractor gizmos with G
+	if rl.IsKeyPressed(rl.KeyG) {
+		s.showGizmos = !s.showGizmos
+	}
+
 	// Toggle quality with Q key
 	if rl.IsKeyPressed(rl.KeyQ) {
-		s.quality = premium.GetQualitySettings(premium.NextQuality(s.quality.Level))
+		s.changeQuality(premium.NextQuality(s.quality.Level))
 	}
 
 	// Update effects
@@ -90,44 +244,111 @@ func (s *renderSystem) Process(em ecs.EntityManager) (state int) {
 	s.uiState.Update(dt, rl.GetMouseDelta().X != 0 || rl.GetMouseDelta().Y != 0)
 
 	rl.BeginDrawing()
-	rl.ClearBackground(rl.NewColor(10, 10, 20, 255))
+	background := s.background.Color()
+	rl.ClearBackground(background)
 
-	particles := em.FilterByMask(components.MaskRenderable)
+	// Stars move with the camera's pan
+	camera := s.camera2D(em)
+	s.camera = camera
+	panX, panY := camera.Target.X-camera.Offset.X, camera.Target.Y-camera.Offset.Y
+	now := float32(rl.GetTime())
 
 	// Apply screen shake offset
 	shakeX, shakeY := s.effects.GetShakeOffset()
 
-	for _, e := range particles {
-		pos := e.Get(components.MaskPosition).(*components.Position)
-		col := e.Get(components.MaskColor).(*components.Color)
-		size := e.Get(components.MaskSize).(*components.Size)
-
-		drawX := int32(pos.X + shakeX)
-		drawY := int32(pos.Y + shakeY)
-		drawColor := rl.NewColor(col.R, col.G, col.B, col.A)
-
-		// Glow effect (if enabled)
-		if s.quality.GlowEnabled && s.palette.GlowIntensity > 0 {
-			glowAlpha := uint8(float32(col.A) * s.palette.GlowIntensity * 0.3)
-			glowColor := rl.NewColor(s.palette.GlowR, s.palette.GlowG, s.palette.GlowB, glowAlpha)
-			// Draw glow layers
-			for i := 0; i < s.quality.GlowPasses; i++ {
-				glowSize := size.Radius * (2.0 + float32(i)*1.5)
-				rl.DrawCircle(drawX, drawY, glowSize, glowColor)
-			}
+	s.batch.ResetStats()
+	s.direct.counter.reset()
+
+	particleCount := s.bucketLayers(em)
+	if s.store != nil {
+		particleCount += s.store.Len()
+	}
+
+	// Metaball densities are drawn before the scene, as render textures
+	// cannot nest
+	s.metaballsActive = s.metaballs.Active() && s.layers[components.LayerParticles].visible
+	if s.metaballsActive {
+		s.drawMetaballs(camera, shakeX, shakeY)
+	}
+
+	// With lighting, the lit background and background layer replace the
+	// plain background
+	s.bloomActive = s.passes&PassBloom != 0 && s.bloom.Active()
+	drawBackground := func(alpha uint8) {
+		s.background.Draw(panX, panY, now, alpha)
+	}
+	if s.passes&PassLighting != 0 && s.lights.Active() {
+		s.drawLights(camera, shakeX, shakeY)
+		s.drawLit(camera, panX, panY, now, shakeX, shakeY)
+		drawBackground = s.lights.DrawLit
+	}
+
+	// A persistent background accumulates the scene in its own texture,
+	// fading the previous frame instead of clearing it. Otherwise the
+	// scene is drawn into the bloom scene texture when bloom is on, or
+	// straight into the post-process input.
+	postFX := s.passes&PassPostFX != 0 && s.postfx.Active()
+	persistent := s.background.Persistent()
+	if persistent {
+		s.background.BeginPersistent(drawBackground)
+	} else {
+		if s.bloomActive {
+			s.bloom.Begin(background)
+		} else if postFX {
+			s.postfx.Begin(background)
 		}
+		drawBackground(255)
+	}
+
+	rl.BeginMode2D(camera)
+	for layer := components.LayerBackground; layer < components.LayerUI; layer++ {
+		s.drawLayer(layer, shakeX, shakeY)
+	}
+	rl.EndMode2D()
+
+	// Scenes drawn offscreen are composited to the screen, or into the
+	// post-process input
+	if persistent || s.bloomActive {
+		var scene rl.RenderTexture2D
+		if persistent {
+			scene = s.background.EndPersistent()
+		} else {
+			rl.EndTextureMode()
+			scene = s.bloom.scene
+		}
+		if s.bloomActive {
+			s.bloom.blur(scene)
+		}
+		if postFX {
+			s.postfx.Begin(background)
+		}
+		if s.bloomActive {
+			s.bloom.draw(scene)
+		} else {
+			copyTarget(scene, float32(s.width), float32(s.height))
+		}
+	}
+	if postFX {
+		s.postfx.End(now)
+	}
 
-		// Main particle
-		rl.DrawCircle(drawX, drawY, size.Radius, drawColor)
+	// The flash covers the world but not the UI
+	if a := s.effects.GetFlashAlpha(); a > 0 {
+		rl.DrawRectangle(0, 0, s.width, s.height, rl.NewColor(255, 255, 255, uint8(a*255)))
 	}
 
+	// The UI layer is neither shaken, post-processed nor seen through the
+	// camera
+	s.drawLayer(components.LayerUI, 0, 0)
+	s.particleDraws = s.batch.DrawCalls() + s.direct.counter.draws
+
 	// UI with fade alpha
 	uiAlpha := uint8(s.uiState.GetControlsAlpha() * 255)
 
 	if s.showDebug {
 		rl.DrawFPS(10, 10)
 		rl.DrawText(
-			fmt.Sprintf("Entities: %d", len(particles)),
+			fmt.Sprintf("Entities: %d", particleCount),
 			10, 35, 20, rl.White,
 		)
 		rl.DrawText(
@@ -144,20 +365,34 @@ func (s *renderSystem) Process(em ecs.EntityManager) (state int) {
 			fmt.Sprintf("Mouse: (%d, %d)", mouseX, mouseY),
 			10, 110, 16, rl.Gray,
 		)
+		mode := "immediate"
+		if s.batched {
+			mode = "batched"
+		}
+		rl.DrawText(
+			fmt.Sprintf("Draw calls: %d (%s)", s.particleDraws, mode),
+			10, 130, 16, rl.Gray,
+		)
+		rl.DrawText(
+			fmt.Sprintf("Juice: %s", s.juiceLevel),
+			10, 150, 16, rl.Gray,
+		)
 	}
 
 	// Controls hint with fade
 	if uiAlpha > 10 {
+		x, y := s.layout.BottomLeft()
 		rl.DrawText(
-			"F3: Debug | Q: Quality | F/F11: Fullscreen | ESC: Exit Fullscreen | 1-5: Presets",
-			10, s.height-30, 16, rl.NewColor(150, 150, 150, uiAlpha),
+			"F3: Debug | F4: Trails | G: Gizmos | B: Batching | Q: Quality | J: Juice | F/F11: Fullscreen | ESC: Exit Fullscreen | 1-5: Presets",
+			int32(x), int32(y)-20, 16, rl.NewColor(150, 150, 150, uiAlpha),
 		)
 	}
 
 	// Particle count slider (always visible in debug mode)
 	if s.showDebug {
-		sliderX := s.width - 250
-		sliderY := int32(10)
+		right, top := s.layout.TopRight()
+		sliderX := int32(right) - 240
+		sliderY := int32(top)
 		sliderWidth := int32(200)
 		sliderHeight := int32(20)
 
@@ -198,8 +433,8 @@ func (s *renderSystem) Process(em ecs.EntityManager) (state int) {
 		}
 
 		// Quality buttons
-		qx := s.width - 250
-		qy := int32(55)
+		qx := sliderX
+		qy := sliderY + 45
 		rl.DrawText("Quality:", qx, qy, 14, rl.White)
 
 		qualities := []string{"Low", "Med", "High"}
@@ -222,7 +457,7 @@ func (s *renderSystem) Process(em ecs.EntityManager) (state int) {
 			if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
 				if mouseX >= btnX && mouseX <= btnX+btnW &&
 					mouseY >= qy && mouseY <= qy+btnH {
-					s.quality = premium.GetQualitySettings(premium.QualityLevel(i))
+					s.changeQuality(premium.QualityLevel(i))
 				}
 			}
 		}
@@ -233,10 +468,150 @@ func (s *renderSystem) Process(em ecs.EntityManager) (state int) {
 	return ecs.StateEngineContinue
 }
 
+// beginBlendMode switches to the blend mode particles are drawn with next.
+func (s *renderSystem) beginBlendMode(mode components.BlendMode) {
+	s.drawMode = mode
+	beginBlend(mode)
+}
+
+// resize adapts the render system to a new window size and reports it to
+// the OnResize callback.
+func (s *renderSystem) resize(width, height int32) {
+	s.width, s.height = width, height
+	s.layout.Resize(int(width), int(height))
+	s.bloom.Resize(width, height)
+	s.background.Resize(width, height)
+	s.postfx.Resize(width, height)
+	s.metaballs.Resize(width, height)
+	s.lights.Resize(width, height)
+	if s.onResize != nil {
+		s.onResize(width, height)
+	}
+}
+
+// SetOnResize sets the callback invoked with the new size when the window
+// is resized or toggled fullscreen, to update world bounds.
+func (s *renderSystem) SetOnResize(callback func(width, height int32)) {
+	s.onResize = callback
+}
+
+// camera2D returns the view of the first Camera entity, or of the whole
+// window if there is none, zoomed by the screen pulse. The pulse is stored
+// in the Camera, so input maps the cursor through the same zoom.
+func (s *renderSystem) camera2D(em ecs.EntityManager) rl.Camera2D {
+	w, h := float32(s.width), float32(s.height)
+	pulse := s.effects.GetPulseScale()
+	cam := rl.Camera2D{
+		Offset: rl.NewVector2(w/2, h/2),
+		Target: rl.NewVector2(w/2, h/2),
+		Zoom:   pulse,
+	}
+	if c := findCamera(em); c != nil {
+		c.Pulse = pulse
+		cam.Target = rl.NewVector2(c.X, c.Y)
+		cam.Rotation = c.Rotation
+		cam.Zoom = c.ViewZoom()
+	}
+	return cam
+}
+
+// drawer returns the drawer particles are drawn with.
+func (s *renderSystem) drawer() particleDrawer {
+	if s.batched {
+		return s.batch
+	}
+	return &s.direct
+}
+
+// flush draws the batched particles. It must be called before the blend
+// mode changes.
+func (s *renderSystem) flush() {
+	if s.batched {
+		s.batch.Flush()
+	}
+}
+
+// drawEntity draws a renderable entity offset by the screen shake.
+func (s *renderSystem) drawEntity(e *ecs.Entity, shakeX, shakeY float32) {
+	pos := e.Get(components.MaskPosition).(*components.Position)
+	col := e.Get(components.MaskColor).(*components.Color)
+	size := e.Get(components.MaskSize).(*components.Size)
+
+	if e.Masked&components.MaskSprite != 0 {
+		sprite := e.Get(components.MaskSprite).(*components.Sprite)
+		if sheet, ok := s.sprites[sprite.Sheet]; ok {
+			s.drawSpriteEntity(e, sheet, sprite, pos.X+shakeX, pos.Y+shakeY, size.Radius, col)
+			return
+		}
+	}
+
+	var vx, vy float32
+	if e.Masked&components.MaskVelocity != 0 {
+		vel := e.Get(components.MaskVelocity).(*components.Velocity)
+		vx, vy = vel.X, vel.Y
+	}
+
+	s.drawParticle(pos.X+shakeX, pos.Y+shakeY, vx, vy, size.Radius, col.R, col.G, col.B, col.A)
+}
+
+// drawSpriteEntity draws an entity with its sprite frame at screen
+// position (x, y), behind its glow.
+func (s *renderSystem) drawSpriteEntity(e *ecs.Entity, sheet *SpriteSheet, sprite *components.Sprite, x, y, radius float32, col *components.Color) {
+	var age, progress float32
+	if e.Masked&components.MaskLifetime != 0 {
+		life := e.Get(components.MaskLifetime).(*components.Lifetime)
+		age, progress = life.Age, life.Progress()
+	}
+	var angle float32
+	if e.Masked&components.MaskRotation != 0 {
+		angle = e.Get(components.MaskRotation).(*components.Rotation).Angle
+	}
+
+	// Scenery sprites, without a Particle component, do not glow
+	r, g, b, a := col.R, col.G, col.B, col.A
+	if s.drawMode == components.BlendScreen {
+		r, g, b, a = premultiply(r, g, b, a)
+	} else if s.passes&PassGlow != 0 && !s.bloomActive && e.Masked&components.MaskParticle != 0 {
+		s.glow.draw(s.drawer(), x, y, radius, r, g, b, a)
+	}
+	s.drawer().Sprite(sheet, sprite.FrameAt(age, progress), x, y, radius*sprite.Scale, angle, r, g, b, a)
+}
+
+// drawParticle draws one particle moving with velocity (vx, vy) at screen
+// position (x, y) through the glow and motion blur passes. In screen
+// blending the glow is skipped, as its translucent layers would be drawn
+// opaque.
+func (s *renderSystem) drawParticle(x, y, vx, vy, radius float32, r, g, b, a uint8) {
+	if s.drawMode == components.BlendScreen {
+		r, g, b, a = premultiply(r, g, b, a)
+	} else if s.passes&PassGlow != 0 && !s.bloomActive {
+		s.glow.draw(s.drawer(), x, y, radius, r, g, b, a)
+	}
+	if s.passes&PassMotionBlur == 0 {
+		vx, vy = 0, 0
+	}
+	s.blur.draw(s.drawer(), x, y, vx, vy, radius, r, g, b, a)
+}
+
 func (s *renderSystem) Teardown() {
+	s.bloom.Unload()
+	s.background.Unload()
+	s.postfx.Unload()
+	s.metaballs.Unload()
+	s.lights.Unload()
+	s.batch.Unload()
+	for _, sheet := range s.sprites {
+		sheet.Unload()
+	}
 	rl.CloseWindow()
 }
 
+// UseStore makes the system draw the particles of store after the
+// particle entities.
+func (s *renderSystem) UseStore(store *ParticleStore) {
+	s.store = store
+}
+
 // SetPresetName sets the current preset name for display.
 func (s *renderSystem) SetPresetName(name string) {
 	s.presetName = name
@@ -245,11 +620,124 @@ func (s *renderSystem) SetPresetName(name string) {
 // SetPalette sets the color palette for glow effects.
 func (s *renderSystem) SetPalette(palette premium.ColorPalette) {
 	s.palette = palette
+	s.glow.SetPalette(palette)
+}
+
+// SetBackground sets what is drawn behind the particles.
+func (s *renderSystem) SetBackground(bg premium.Background) {
+	s.background.SetBackground(bg)
+}
+
+// Background returns the current background.
+func (s *renderSystem) Background() premium.Background {
+	return s.background.Background()
+}
+
+// SetPostEffects sets the post-process effect stack applied to the final
+// frame, in order.
+func (s *renderSystem) SetPostEffects(effects []premium.PostEffect) {
+	s.postfx.SetEffects(effects)
+}
+
+// PostEffects returns the post-process effect stack.
+func (s *renderSystem) PostEffects() []premium.PostEffect {
+	return s.postfx.Effects()
+}
+
+// SetMetaballs draws particles that are not sprites as metaballs with the
+// given configuration; nil draws them as circles.
+func (s *renderSystem) SetMetaballs(config *premium.Metaballs) {
+	s.metaballs.SetMetaballs(config)
+}
+
+// Metaballs returns the metaball configuration, nil if particles are drawn
+// as circles.
+func (s *renderSystem) Metaballs() *premium.Metaballs {
+	return s.metaballs.Metaballs()
+}
+
+// SetLighting lights the background with the particles as light sources;
+// nil turns lighting off.
+func (s *renderSystem) SetLighting(config *premium.Lighting) {
+	s.lights.SetLighting(config)
+}
+
+// Lighting returns the lighting configuration, nil if lighting is off.
+func (s *renderSystem) Lighting() *premium.Lighting {
+	return s.lights.Lighting()
+}
+
+// SetBlendMode sets the blend mode for particles without a Blend
+// component.
+func (s *renderSystem) SetBlendMode(mode components.BlendMode) {
+	s.SetLayerBlendMode(components.LayerParticles, mode)
+}
+
+// BlendMode returns the blend mode for particles without a Blend component.
+func (s *renderSystem) BlendMode() components.BlendMode {
+	return s.LayerBlendMode(components.LayerParticles)
+}
+
+// AddSpriteSheet registers a sprite sheet under the name Sprite
+// components refer to. Entities whose sheet is unknown are drawn as
+// circles.
+func (s *renderSystem) AddSpriteSheet(name string, sheet *SpriteSheet) {
+	s.sprites[name] = sheet
+}
+
+// SetBatched switches between batched and immediate particle drawing.
+func (s *renderSystem) SetBatched(batched bool) {
+	s.batched = batched
+}
+
+// IsBatched returns whether particles are drawn batched.
+func (s *renderSystem) IsBatched() bool {
+	return s.batched
+}
+
+// SetPasses selects the render passes to draw.
+func (s *renderSystem) SetPasses(passes RenderPass) {
+	s.passes = passes
+}
+
+// Passes returns the selected render passes.
+func (s *renderSystem) Passes() RenderPass {
+	return s.passes
 }
 
 // SetQuality sets the quality level.
 func (s *renderSystem) SetQuality(level premium.QualityLevel) {
 	s.quality = premium.GetQualitySettings(level)
+	s.applyQuality()
+}
+
+// applyQuality configures the pass renderers for the current quality.
+func (s *renderSystem) applyQuality() {
+	s.trails.ApplyQuality(s.quality)
+	s.glow.ApplyQuality(s.quality)
+	s.blur.ApplyQuality(s.quality)
+	s.bloom.ApplyQuality(s.quality)
+	s.postfx.ApplyQuality(s.quality)
+}
+
+// changeQuality sets the quality level chosen by the user and notifies
+// the quality callback.
+func (s *renderSystem) changeQuality(level premium.QualityLevel) {
+	s.SetQuality(level)
+	if s.onQualityChange != nil {
+		s.onQualityChange(level)
+	}
+}
+
+// SetOnQualityChange sets the callback for when the user changes the
+// quality level.
+func (s *renderSystem) SetOnQualityChange(callback func(premium.QualityLevel)) {
+	s.onQualityChange = callback
+}
+
+// SetTrailStyle sets how particle trails are drawn.
+func (s *renderSystem) SetTrailStyle(style TrailStyle) {
+	s.trails.SetStyle(style)
 }
 
 // GetQuality returns the current quality settings.
@@ -267,6 +755,16 @@ func (s *renderSystem) ApplyPulse(scale, duration float32) {
 	s.effects.ApplyPulse(scale, duration)
 }
 
+// ApplyFlash triggers a white screen flash.
+func (s *renderSystem) ApplyFlash(alpha, duration float32) {
+	s.effects.ApplyFlash(alpha, duration)
+}
+
+// SetJuiceLevel sets the juice level for display.
+func (s *renderSystem) SetJuiceLevel(level premium.JuiceLevel) {
+	s.juiceLevel = level
+}
+
 // SetMaxParticles sets the max particles for the slider display.
 func (s *renderSystem) SetMaxParticles(max int) {
 	s.maxParticles = int32(max)
@@ -0,0 +1,134 @@
+package systems
+
+import "math"
+
+// RateCurve maps the age of an emitter (seconds since the curve was set)
+// to a spawn rate in particles per second.
+//
+// Curves let the EmitterSystem ramp emission up and down over time instead
+// of spawning at the fixed rate passed to SetSpawnRate.
+type RateCurve interface {
+	Rate(age float32) float32
+}
+
+// RateFunc adapts an ordinary function to the RateCurve interface.
+//
+// Example of a linear ramp from 0 to 200 particles/s over 4 seconds:
+//
+//	emitter.SetSpawnRateCurve(systems.RateFunc(func(age float32) float32 {
+//	    return 200 * min(age/4, 1)
+//	}))
+type RateFunc func(age float32) float32
+
+// Rate calls f(age).
+func (f RateFunc) Rate(age float32) float32 { return f(age) }
+
+// Envelope is a spawn rate envelope with ramp-up, sustain, decay and rest
+// phases, optionally looping and modulated by a sinusoidal pulse.
+//
+//	rate
+//	Peak  |     ________
+//	      |    /        \
+//	Floor |___/          \_____
+//	      +-------------------- age
+//	        RampUp Sustain Decay Rest
+//
+// With Loop enabled the envelope restarts after the rest phase, which
+// produces rhythmic bursts (e.g. one firework volley every few seconds).
+type Envelope struct {
+	// Peak is the spawn rate at full level in particles per second.
+	Peak float32
+	// Floor is the spawn rate outside of the active phases.
+	Floor float32
+	// RampUp is the time in seconds to rise from Floor to Peak.
+	RampUp float32
+	// Sustain is the time in seconds spent at Peak.
+	Sustain float32
+	// Decay is the time in seconds to fall from Peak back to Floor.
+	Decay float32
+	// Rest is the time in seconds spent at Floor before looping.
+	Rest float32
+	// Loop restarts the envelope after the rest phase.
+	Loop bool
+	// PulseHz is the frequency of an optional sinusoidal modulation.
+	PulseHz float32
+	// PulseDepth scales the modulation (0 = none, 1 = rate drops to zero
+	// at the pulse trough).
+	PulseDepth float32
+}
+
+// NewEnvelope creates a non-looping envelope that ramps from zero to peak,
+// holds it and then decays back to zero.
+func NewEnvelope(peak, rampUp, sustain, decay float32) Envelope {
+	return Envelope{
+		Peak:    peak,
+		RampUp:  rampUp,
+		Sustain: sustain,
+		Decay:   decay,
+	}
+}
+
+// NewPulse creates a looping envelope that alternates between bursts of
+// peak-rate emission and silence.
+func NewPulse(peak, on, off float32) Envelope {
+	return Envelope{
+		Peak:    peak,
+		Sustain: on,
+		Rest:    off,
+		Loop:    true,
+	}
+}
+
+// WithLoop enables looping and returns the envelope for chaining.
+func (e Envelope) WithLoop(rest float32) Envelope {
+	e.Loop = true
+	e.Rest = rest
+	return e
+}
+
+// WithPulse adds sinusoidal modulation and returns the envelope for chaining.
+func (e Envelope) WithPulse(hz, depth float32) Envelope {
+	e.PulseHz = hz
+	e.PulseDepth = depth
+	return e
+}
+
+// Duration returns the length of one envelope cycle in seconds.
+func (e Envelope) Duration() float32 {
+	return e.RampUp + e.Sustain + e.Decay + e.Rest
+}
+
+// Rate returns the spawn rate at the given age.
+func (e Envelope) Rate(age float32) float32 {
+	if age < 0 {
+		age = 0
+	}
+	if d := e.Duration(); e.Loop && d > 0 {
+		age = float32(math.Mod(float64(age), float64(d)))
+	}
+
+	var level float32
+	switch {
+	case age < e.RampUp:
+		level = age / e.RampUp
+	case age < e.RampUp+e.Sustain:
+		level = 1
+	case age < e.RampUp+e.Sustain+e.Decay:
+		level = 1 - (age-e.RampUp-e.Sustain)/e.Decay
+	default:
+		level = 0
+	}
+
+	rate := e.Floor + (e.Peak-e.Floor)*level
+
+	if e.PulseHz > 0 && e.PulseDepth > 0 {
+		phase := float64(age) * float64(e.PulseHz) * 2 * math.Pi
+		mod := 1 - e.PulseDepth*0.5*(1-float32(math.Cos(phase)))
+		rate *= mod
+	}
+
+	if rate < 0 {
+		return 0
+	}
+	return rate
+}
@@ -0,0 +1,115 @@
+package systems
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// SpriteSheet is a texture atlas split into a grid of equally sized
+// frames, numbered left to right, top to bottom. Particles with a Sprite
+// component are drawn with one of its frames.
+//
+// The image is kept on the CPU until the sheet is first drawn, so sheets
+// can be created before the window exists.
+type SpriteSheet struct {
+	image          *image.NRGBA
+	frameW, frameH int
+	columns        int
+	frames         int
+	texture        rl.Texture2D
+	loaded         bool
+}
+
+// NewSpriteSheet creates a sprite sheet from img with frames of
+// frameWidth × frameHeight pixels. Frames larger than the image are
+// clamped to the image size.
+func NewSpriteSheet(img image.Image, frameWidth, frameHeight int) *SpriteSheet {
+	b := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), img, b.Min, draw.Src)
+
+	fw := min(max(frameWidth, 1), max(b.Dx(), 1))
+	fh := min(max(frameHeight, 1), max(b.Dy(), 1))
+	columns := max(b.Dx()/fw, 1)
+	return &SpriteSheet{
+		image:   nrgba,
+		frameW:  fw,
+		frameH:  fh,
+		columns: columns,
+		frames:  columns * max(b.Dy()/fh, 1),
+	}
+}
+
+// LoadSpriteSheet decodes a PNG atlas with frames of frameWidth ×
+// frameHeight pixels.
+func LoadSpriteSheet(path string, frameWidth, frameHeight int) (*SpriteSheet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	if b := img.Bounds(); frameWidth > b.Dx() || frameHeight > b.Dy() {
+		return nil, fmt.Errorf("sprite sheet %s: %dx%d frames do not fit into %dx%d image",
+			path, frameWidth, frameHeight, b.Dx(), b.Dy())
+	}
+	return NewSpriteSheet(img, frameWidth, frameHeight), nil
+}
+
+// Frames returns the number of frames in the sheet.
+func (s *SpriteSheet) Frames() int {
+	return s.frames
+}
+
+// FrameRect returns the source rectangle of frame i in texture pixels.
+// Frame numbers wrap around, so animations never run off the sheet.
+func (s *SpriteSheet) FrameRect(i int) rl.Rectangle {
+	i %= s.frames
+	if i < 0 {
+		i += s.frames
+	}
+	x, y := i%s.columns, i/s.columns
+	return rl.NewRectangle(float32(x*s.frameW), float32(y*s.frameH), float32(s.frameW), float32(s.frameH))
+}
+
+// Texture returns the sheet's texture, uploading the image on first use.
+// It must be called after the window has been created.
+func (s *SpriteSheet) Texture() rl.Texture2D {
+	if !s.loaded {
+		b := s.image.Bounds()
+		img := rl.NewImage(s.image.Pix, int32(b.Dx()), int32(b.Dy()), 1, rl.UncompressedR8g8b8a8)
+		s.texture = rl.LoadTextureFromImage(img)
+		rl.SetTextureFilter(s.texture, rl.FilterBilinear)
+		s.loaded = true
+	}
+	return s.texture
+}
+
+// Unload releases the texture. The sheet uploads it again when drawn.
+func (s *SpriteSheet) Unload() {
+	if s.loaded {
+		rl.UnloadTexture(s.texture)
+		s.loaded = false
+	}
+}
+
+// drawSprite draws a frame of sheet centered at (x, y), scaled to a
+// diameter of 2 × radius, rotated by angle degrees and tinted with the
+// color.
+func drawSprite(sheet *SpriteSheet, frame int, x, y, radius, angle float32, r, g, b, a uint8) {
+	src := sheet.FrameRect(frame)
+	// Keep the frame's aspect ratio, fitting its larger side
+	scale := 2 * radius / max(src.Width, src.Height)
+	w, h := src.Width*scale, src.Height*scale
+	dst := rl.NewRectangle(x, y, w, h)
+	rl.DrawTexturePro(sheet.Texture(), src, dst, rl.NewVector2(w/2, h/2), angle, rl.NewColor(r, g, b, a))
+}
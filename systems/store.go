@@ -0,0 +1,143 @@
+package systems
+
+import "github.com/andygeiss/ecs"
+
+// RGBA is a packed 8-bit color as stored by ParticleStore.
+type RGBA [4]uint8
+
+// ParticleStore is a struct-of-arrays particle backend. Each particle
+// attribute lives in its own contiguous slice, indexed by particle, so hot
+// systems iterate plain float32 slices instead of looking up components
+// through interfaces.
+//
+// The store is optional: when attached to the systems with AttachStore,
+// EmitterSystem spawns into the store and Gravity, Physics, Lifetime,
+// Color and Render process it in addition to the ECS entities. Attractors,
+// emitters and particles created by presets stay ECS entities.
+//
+// Particles in the store have no identity; Kill swaps the last particle
+// into the freed slot, so indices are only stable within a frame.
+type ParticleStore struct {
+	X, Y   []float32
+	VX, VY []float32
+	AX, AY []float32
+	Age    []float32
+	TTL    []float32
+	// Radius is the current size; it is interpolated from StartSize to
+	// EndSize by the ColorSystem.
+	Radius, StartSize, EndSize []float32
+	// Color is the current color; it is interpolated from StartColor to
+	// EndColor by the ColorSystem.
+	Color, StartColor, EndColor []RGBA
+}
+
+// NewParticleStore creates an empty store with room for capacity particles
+// before any slice grows.
+func NewParticleStore(capacity int) *ParticleStore {
+	return &ParticleStore{
+		X:          make([]float32, 0, capacity),
+		Y:          make([]float32, 0, capacity),
+		VX:         make([]float32, 0, capacity),
+		VY:         make([]float32, 0, capacity),
+		AX:         make([]float32, 0, capacity),
+		AY:         make([]float32, 0, capacity),
+		Age:        make([]float32, 0, capacity),
+		TTL:        make([]float32, 0, capacity),
+		Radius:     make([]float32, 0, capacity),
+		StartSize:  make([]float32, 0, capacity),
+		EndSize:    make([]float32, 0, capacity),
+		Color:      make([]RGBA, 0, capacity),
+		StartColor: make([]RGBA, 0, capacity),
+		EndColor:   make([]RGBA, 0, capacity),
+	}
+}
+
+// Len returns the number of live particles.
+func (s *ParticleStore) Len() int {
+	return len(s.X)
+}
+
+// Spawn appends a zeroed particle and returns its index. The caller sets
+// its attributes directly on the slices.
+func (s *ParticleStore) Spawn() int {
+	s.X = append(s.X, 0)
+	s.Y = append(s.Y, 0)
+	s.VX = append(s.VX, 0)
+	s.VY = append(s.VY, 0)
+	s.AX = append(s.AX, 0)
+	s.AY = append(s.AY, 0)
+	s.Age = append(s.Age, 0)
+	s.TTL = append(s.TTL, 0)
+	s.Radius = append(s.Radius, 0)
+	s.StartSize = append(s.StartSize, 0)
+	s.EndSize = append(s.EndSize, 0)
+	s.Color = append(s.Color, RGBA{})
+	s.StartColor = append(s.StartColor, RGBA{})
+	s.EndColor = append(s.EndColor, RGBA{})
+	return len(s.X) - 1
+}
+
+// Kill removes particle i by moving the last particle into its slot.
+func (s *ParticleStore) Kill(i int) {
+	last := len(s.X) - 1
+	s.X[i], s.X = s.X[last], s.X[:last]
+	s.Y[i], s.Y = s.Y[last], s.Y[:last]
+	s.VX[i], s.VX = s.VX[last], s.VX[:last]
+	s.VY[i], s.VY = s.VY[last], s.VY[:last]
+	s.AX[i], s.AX = s.AX[last], s.AX[:last]
+	s.AY[i], s.AY = s.AY[last], s.AY[:last]
+	s.Age[i], s.Age = s.Age[last], s.Age[:last]
+	s.TTL[i], s.TTL = s.TTL[last], s.TTL[:last]
+	s.Radius[i], s.Radius = s.Radius[last], s.Radius[:last]
+	s.StartSize[i], s.StartSize = s.StartSize[last], s.StartSize[:last]
+	s.EndSize[i], s.EndSize = s.EndSize[last], s.EndSize[:last]
+	s.Color[i], s.Color = s.Color[last], s.Color[:last]
+	s.StartColor[i], s.StartColor = s.StartColor[last], s.StartColor[:last]
+	s.EndColor[i], s.EndColor = s.EndColor[last], s.EndColor[:last]
+}
+
+// Clear removes all particles and keeps the allocated capacity.
+func (s *ParticleStore) Clear() {
+	s.X = s.X[:0]
+	s.Y = s.Y[:0]
+	s.VX = s.VX[:0]
+	s.VY = s.VY[:0]
+	s.AX = s.AX[:0]
+	s.AY = s.AY[:0]
+	s.Age = s.Age[:0]
+	s.TTL = s.TTL[:0]
+	s.Radius = s.Radius[:0]
+	s.StartSize = s.StartSize[:0]
+	s.EndSize = s.EndSize[:0]
+	s.Color = s.Color[:0]
+	s.StartColor = s.StartColor[:0]
+	s.EndColor = s.EndColor[:0]
+}
+
+// Progress returns the lifetime progress of particle i from 0.0 to 1.0,
+// matching components.Lifetime.Progress.
+func (s *ParticleStore) Progress(i int) float32 {
+	if s.TTL[i] <= 0 {
+		return 1.0
+	}
+	p := s.Age[i] / s.TTL[i]
+	if p > 1.0 {
+		return 1.0
+	}
+	return p
+}
+
+// storeUser is implemented by systems that can process a ParticleStore.
+type storeUser interface {
+	UseStore(store *ParticleStore)
+}
+
+// AttachStore attaches store to every system that supports it and ignores
+// the rest, so the whole system list can be passed in.
+func AttachStore(store *ParticleStore, systems ...ecs.System) {
+	for _, sys := range systems {
+		if u, ok := sys.(storeUser); ok {
+			u.UseStore(store)
+		}
+	}
+}
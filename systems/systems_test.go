@@ -1,11 +1,22 @@
 package systems
 
 import (
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"testing"
 
 	"github.com/andygeiss/ecs"
 	"github.com/deltatree/showcase/components"
+	"github.com/deltatree/showcase/curves"
 	"github.com/deltatree/showcase/premium"
+	"github.com/deltatree/showcase/shapes"
+	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 // TestColorSystem tests color interpolation based on lifetime.
@@ -908,3 +919,2155 @@ func TestRenderSystem_DefaultQuality(t *testing.T) {
 		t.Errorf("Default quality should be Medium, got %s", sys.quality.Level.String())
 	}
 }
+
+// TestEnvelope_Rate tests the ramp, sustain, decay and rest phases.
+func TestEnvelope_Rate(t *testing.T) {
+	env := NewEnvelope(100, 1, 2, 1)
+
+	tests := []struct {
+		age  float32
+		want float32
+	}{
+		{0, 0},
+		{0.5, 50},
+		{1.5, 100},
+		{3.5, 50},
+		{5, 0},
+	}
+
+	for _, tc := range tests {
+		got := env.Rate(tc.age)
+		if got < tc.want-0.01 || got > tc.want+0.01 {
+			t.Errorf("Rate(%v) = %v, want %v", tc.age, got, tc.want)
+		}
+	}
+}
+
+// TestEnvelope_Loop tests that looping envelopes repeat each cycle.
+func TestEnvelope_Loop(t *testing.T) {
+	env := NewPulse(200, 0.5, 1.5)
+
+	if env.Duration() != 2 {
+		t.Errorf("Duration() = %v, want 2", env.Duration())
+	}
+	if env.Rate(0.25) != 200 || env.Rate(2.25) != 200 {
+		t.Errorf("expected burst at start of each cycle, got %v and %v", env.Rate(0.25), env.Rate(2.25))
+	}
+	if env.Rate(1.0) != 0 || env.Rate(3.0) != 0 {
+		t.Errorf("expected silence during rest, got %v and %v", env.Rate(1.0), env.Rate(3.0))
+	}
+}
+
+// TestEnvelope_Pulse tests sinusoidal modulation of the rate.
+func TestEnvelope_Pulse(t *testing.T) {
+	env := Envelope{Peak: 100, Sustain: 10}.WithPulse(1, 1)
+
+	if got := env.Rate(0); got != 100 {
+		t.Errorf("Rate at pulse crest = %v, want 100", got)
+	}
+	if got := env.Rate(0.5); got > 0.01 {
+		t.Errorf("Rate at pulse trough = %v, want 0", got)
+	}
+}
+
+// TestEmitterSystem_FixedRate tests that a fixed rate spawns rate*dt particles.
+func TestEmitterSystem_FixedRate(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sys := NewEmitterSystem(100, 5000, 1280, 720)
+
+	sys.update(em, 0.5)
+
+	if got := len(em.FilterByMask(components.MaskParticle)); got != 50 {
+		t.Errorf("spawned %d particles, want 50", got)
+	}
+}
+
+// TestEmitterSystem_RateCurve tests that a rate curve overrides the fixed rate.
+func TestEmitterSystem_RateCurve(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sys := NewEmitterSystem(100, 5000, 1280, 720)
+	sys.SetSpawnRateCurve(RateFunc(func(age float32) float32 {
+		if age < 1 {
+			return 0
+		}
+		return 10
+	}))
+
+	sys.update(em, 0.5)
+	if got := len(em.FilterByMask(components.MaskParticle)); got != 0 {
+		t.Errorf("spawned %d particles during silent phase, want 0", got)
+	}
+
+	for i := 0; i < 10; i++ {
+		sys.update(em, 0.1)
+	}
+	if got := len(em.FilterByMask(components.MaskParticle)); got < 4 || got > 6 {
+		t.Errorf("spawned %d particles, want ~5", got)
+	}
+
+	sys.SetSpawnRate(100)
+	if sys.rateCurve != nil {
+		t.Error("SetSpawnRate should clear the rate curve")
+	}
+}
+
+// TestEmitterSystem_DistanceEmission tests evenly spaced emission along a path.
+func TestEmitterSystem_DistanceEmission(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sys := NewEmitterSystem(0, 5000, 1280, 720)
+	sys.SetDistanceEmission(0.1) // one particle every 10 pixels
+
+	pos := components.NewPosition().With(100, 100)
+	em.Add(ecs.NewEntity("cursor", []ecs.Component{pos, components.NewEmitter()}))
+
+	sys.update(em, 0.016) // first frame only records the start point
+
+	pos.X = 125
+	sys.update(em, 0.016)
+	pos.X = 150
+	sys.update(em, 0.016)
+
+	particles := em.FilterByMask(components.MaskParticle)
+	if len(particles) != 5 {
+		t.Fatalf("spawned %d particles over 50px, want 5", len(particles))
+	}
+	for i, p := range particles {
+		x := p.Get(components.MaskPosition).(*components.Position).X
+		want := float32(110 + i*10)
+		if x < want-0.01 || x > want+0.01 {
+			t.Errorf("particle %d at x=%v, want %v", i, x, want)
+		}
+	}
+}
+
+// TestEmitterSystem_EmitterPattern tests spawning at emitter entity positions.
+func TestEmitterSystem_EmitterPattern(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sys := NewEmitterSystem(10, 5000, 1280, 720)
+	sys.SetSpawnPattern("emitter")
+
+	em.Add(ecs.NewEntity("emitter", []ecs.Component{
+		components.NewPosition().With(42, 24),
+		components.NewEmitter(),
+	}))
+
+	sys.update(em, 1)
+
+	for _, p := range em.FilterByMask(components.MaskParticle) {
+		pos := p.Get(components.MaskPosition).(*components.Position)
+		if pos.X != 42 || pos.Y != 24 {
+			t.Errorf("particle spawned at (%v, %v), want (42, 24)", pos.X, pos.Y)
+		}
+	}
+}
+
+// TestEmitterSystem_SpawnMask tests spawning on mask points with sampled colors.
+func TestEmitterSystem_SpawnMask(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sys := NewEmitterSystem(20, 5000, 1280, 720)
+	sys.SetSpawnMask(shapes.PointSet{
+		{X: 5, Y: 5, R: 10, G: 20, B: 30, A: 255},
+		{X: 7, Y: 9, R: 10, G: 20, B: 30, A: 255},
+	}, true)
+
+	if sys.SpawnPattern != "mask" {
+		t.Errorf("SetSpawnMask should switch pattern to 'mask', got %q", sys.SpawnPattern)
+	}
+
+	sys.update(em, 1)
+
+	particles := em.FilterByMask(components.MaskParticle)
+	if len(particles) != 20 {
+		t.Fatalf("spawned %d particles, want 20", len(particles))
+	}
+	for _, p := range particles {
+		pos := p.Get(components.MaskPosition).(*components.Position)
+		col := p.Get(components.MaskColor).(*components.Color)
+		if !(pos.X == 5 && pos.Y == 5) && !(pos.X == 7 && pos.Y == 9) {
+			t.Errorf("particle spawned off-mask at (%v, %v)", pos.X, pos.Y)
+		}
+		if col.StartR != 10 || col.StartG != 20 || col.StartB != 30 {
+			t.Errorf("particle start color = (%d, %d, %d), want sampled (10, 20, 30)", col.StartR, col.StartG, col.StartB)
+		}
+	}
+}
+
+// TestEmitterSystem_EmptySpawnMask tests that an empty mask spawns nothing.
+func TestEmitterSystem_EmptySpawnMask(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sys := NewEmitterSystem(20, 5000, 1280, 720)
+	sys.SetSpawnMask(nil, false)
+
+	sys.update(em, 1)
+
+	if got := len(em.FilterByMask(components.MaskParticle)); got != 0 {
+		t.Errorf("spawned %d particles with empty mask, want 0", got)
+	}
+}
+
+// TestTargetSystem_Process tests that particles are steered towards their target.
+func TestTargetSystem_Process(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sys := NewTargetSystem()
+
+	acc := components.NewAcceleration()
+	em.Add(ecs.NewEntity("p", []ecs.Component{
+		components.NewPosition().With(100, 100),
+		components.NewVelocity(),
+		acc,
+		components.NewTarget().With(200, 50),
+	}))
+
+	if sys.Process(em) != ecs.StateEngineContinue {
+		t.Error("expected StateEngineContinue")
+	}
+	if acc.X <= 0 || acc.Y >= 0 {
+		t.Errorf("expected acceleration towards target, got (%f, %f)", acc.X, acc.Y)
+	}
+}
+
+// TestTargetSystem_Inactive tests that inactive targets are ignored.
+func TestTargetSystem_Inactive(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sys := NewTargetSystem()
+
+	acc := components.NewAcceleration()
+	target := components.NewTarget().With(200, 50)
+	target.Active = false
+	em.Add(ecs.NewEntity("p", []ecs.Component{
+		components.NewPosition().With(100, 100),
+		components.NewVelocity(),
+		acc,
+		target,
+	}))
+
+	sys.Process(em)
+	if acc.X != 0 || acc.Y != 0 {
+		t.Errorf("inactive target should not steer, got (%f, %f)", acc.X, acc.Y)
+	}
+}
+
+// TestLifetimeSystem_HoldsTargets tests that particles on a target do not age.
+func TestLifetimeSystem_HoldsTargets(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sys := NewLifetimeSystem()
+
+	held := components.NewLifetime().WithTTL(1)
+	free := components.NewLifetime().WithTTL(1)
+	em.Add(ecs.NewEntity("held", []ecs.Component{held, components.NewTarget()}))
+	em.Add(ecs.NewEntity("free", []ecs.Component{free}))
+
+	sys.update(em, 2)
+
+	if held.Age != 0 {
+		t.Errorf("held particle aged to %v, want 0", held.Age)
+	}
+	if em.Get("free") != nil {
+		t.Error("free particle should have expired")
+	}
+	if em.Get("held") == nil {
+		t.Error("held particle should still exist")
+	}
+}
+
+// TestParticlePool_Recycles tests that released particles are reused.
+func TestParticlePool_Recycles(t *testing.T) {
+	pool := NewParticlePool()
+	p := pool.Acquire()
+	p.Entity.Add(components.NewTarget())
+	p.Acceleration.Add(3, 4)
+	p.Lifetime.Age = 2
+	p.Lifetime.Expired = true
+
+	if !pool.Release(p.Entity) {
+		t.Fatal("Release should accept a particle entity")
+	}
+	if pool.Free() != 1 {
+		t.Errorf("Free() = %d, want 1", pool.Free())
+	}
+
+	q := pool.Acquire()
+	if q.Entity != p.Entity {
+		t.Error("Acquire should reuse the released entity")
+	}
+	if pool.Allocated() != 1 {
+		t.Errorf("Allocated() = %d, want 1", pool.Allocated())
+	}
+	if q.Entity.Get(components.MaskTarget) != nil {
+		t.Error("recycled entity should not keep extra components")
+	}
+	if q.Entity.Masked != components.MaskFullParticle {
+		t.Errorf("Masked = %b, want %b", q.Entity.Masked, components.MaskFullParticle)
+	}
+	if q.Acceleration.X != 0 || q.Acceleration.Y != 0 {
+		t.Error("recycled acceleration should be reset")
+	}
+	if q.Lifetime.Age != 0 || q.Lifetime.Expired {
+		t.Error("recycled lifetime should be reset")
+	}
+}
+
+// TestParticlePool_RejectsForeignEntities tests that entities without the
+// particle layout are not recycled.
+func TestParticlePool_RejectsForeignEntities(t *testing.T) {
+	pool := NewParticlePool()
+	e := ecs.NewEntity("attractor", []ecs.Component{
+		components.NewPosition(),
+		components.NewMass(),
+	})
+	if pool.Release(e) {
+		t.Error("Release should reject non-particle entities")
+	}
+	if pool.Free() != 0 {
+		t.Errorf("Free() = %d, want 0", pool.Free())
+	}
+}
+
+// TestParticlePool_IgnoresDoubleRelease tests that a particle released
+// twice is handed out once, and can be released again after reuse.
+func TestParticlePool_IgnoresDoubleRelease(t *testing.T) {
+	pool := NewParticlePool()
+	e := pool.Acquire().Entity
+	if !pool.Release(e) || pool.Release(e) {
+		t.Fatal("Release should recycle a particle once")
+	}
+	if pool.Free() != 1 {
+		t.Fatalf("Free() = %d, want 1", pool.Free())
+	}
+	if pool.Acquire().Entity != e || !pool.Release(e) {
+		t.Error("a reused particle should be recycled again")
+	}
+}
+
+// TestLifetimeSystem_ReleasesToPool tests that expired particles return to
+// the emitter pool and are reused by the next spawn.
+func TestLifetimeSystem_ReleasesToPool(t *testing.T) {
+	em := ecs.NewEntityManager()
+	emitter := NewEmitterSystem(0, 100, 1280, 720)
+	emitter.MinTTL, emitter.MaxTTL = 1, 1
+	lifetime := NewLifetimeSystem()
+	lifetime.SetPool(emitter.GetPool())
+
+	emitter.spawnParticle(em)
+	lifetime.update(em, 2)
+
+	if n := len(em.FilterByMask(components.MaskParticle)); n != 0 {
+		t.Fatalf("expected expired particle to be removed, got %d", n)
+	}
+	if emitter.GetPool().Free() != 1 {
+		t.Fatalf("Free() = %d, want 1", emitter.GetPool().Free())
+	}
+
+	emitter.spawnParticle(em)
+	if emitter.GetPool().Allocated() != 1 {
+		t.Errorf("Allocated() = %d, want 1", emitter.GetPool().Allocated())
+	}
+}
+
+// TestLifetimeSystem_ReleasesExactParticle tests that with the default
+// entity manager an expiring preset particle is removed and recycled
+// without touching a live one.
+func TestLifetimeSystem_ReleasesExactParticle(t *testing.T) {
+	em := ecs.NewEntityManager()
+	particle := func(ttl float32) *ecs.Entity {
+		return ecs.NewEntity(components.NewParticleID(), []ecs.Component{
+			components.NewPosition(),
+			components.NewVelocity(),
+			components.NewAcceleration(),
+			components.NewColor(),
+			components.NewLifetime().WithTTL(ttl),
+			components.NewSize(),
+			components.NewParticle(),
+		})
+	}
+	live, expiring := particle(10), particle(1)
+	em.Add(live, expiring)
+	pool := NewParticlePool()
+	lifetime := NewLifetimeSystem()
+	lifetime.SetPool(pool)
+
+	lifetime.update(em, 2)
+	if got := em.Entities(); len(got) != 1 || got[0] != live {
+		t.Fatalf("entities = %v, want only the live particle", got)
+	}
+	if pool.Free() != 1 || pool.Acquire().Entity != expiring {
+		t.Error("the expired particle should be recycled")
+	}
+}
+
+// benchmarkParticleChurn runs emitter and lifetime at a steady state of
+// 240 spawns/sec, with or without recycling expired particles.
+func benchmarkParticleChurn(b *testing.B, pooled bool) {
+	em := ecs.NewEntityManager()
+	emitter := NewEmitterSystem(240, 100000, 1280, 720)
+	emitter.MinTTL, emitter.MaxTTL = 0.5, 0.5
+	lifetime := NewLifetimeSystem()
+	if pooled {
+		lifetime.SetPool(emitter.GetPool())
+	}
+
+	const dt = float32(1.0 / 60)
+	for i := 0; i < 120; i++ {
+		emitter.update(em, dt)
+		lifetime.update(em, dt)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		emitter.update(em, dt)
+		lifetime.update(em, dt)
+	}
+}
+
+// BenchmarkParticleChurn_Allocating measures a frame without recycling.
+func BenchmarkParticleChurn_Allocating(b *testing.B) {
+	benchmarkParticleChurn(b, false)
+}
+
+// BenchmarkParticleChurn_Pooled measures a frame with recycling.
+func BenchmarkParticleChurn_Pooled(b *testing.B) {
+	benchmarkParticleChurn(b, true)
+}
+
+// TestParticleStore_SpawnKill tests that Kill swaps the last particle into
+// the freed slot.
+func TestParticleStore_SpawnKill(t *testing.T) {
+	st := NewParticleStore(4)
+	for i := 0; i < 3; i++ {
+		j := st.Spawn()
+		st.X[j] = float32(i)
+	}
+	if st.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", st.Len())
+	}
+
+	st.Kill(0)
+	if st.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", st.Len())
+	}
+	if st.X[0] != 2 || st.X[1] != 1 {
+		t.Errorf("X = %v, want [2 1]", st.X)
+	}
+
+	st.Clear()
+	if st.Len() != 0 {
+		t.Errorf("Len() after Clear = %d, want 0", st.Len())
+	}
+}
+
+// TestAttachStore tests that the emitter spawns into the store and that
+// the other systems process its particles.
+func TestAttachStore(t *testing.T) {
+	em := ecs.NewEntityManager()
+	st := NewParticleStore(16)
+
+	emitter := NewEmitterSystem(0, 100, 1280, 720)
+	emitter.MinTTL, emitter.MaxTTL = 1, 1
+	emitter.MinVel, emitter.MaxVel = 0, 0
+	gravity := NewGravitySystem()
+	physics := NewPhysicsSystem(1, 500, 1280, 720)
+	lifetime := NewLifetimeSystem()
+	color := NewColorSystem()
+	AttachStore(st, emitter, gravity, physics, lifetime, color, NewTargetSystem())
+
+	em.Add(ecs.NewEntity("attractor", []ecs.Component{
+		components.NewPosition().With(1000, 500),
+		components.NewMass().WithValue(1000),
+		components.NewAttractor(),
+	}))
+
+	emitter.spawnParticleAt(em, 500, 500)
+	if st.Len() != 1 {
+		t.Fatalf("store Len() = %d, want 1", st.Len())
+	}
+	if n := len(em.FilterByMask(components.MaskParticle)); n != 0 {
+		t.Errorf("expected no particle entities, got %d", n)
+	}
+
+	gravity.Process(em)
+	if st.AX[0] <= 0 {
+		t.Errorf("store particle should accelerate towards attractor, AX = %v", st.AX[0])
+	}
+
+	physics.update(em, 0.1)
+	if st.X[0] <= 500 {
+		t.Errorf("store particle should move right, X = %v", st.X[0])
+	}
+
+	lifetime.update(em, 0.5)
+	color.Process(em)
+	if st.Radius[0] >= st.StartSize[0] {
+		t.Errorf("radius should shrink, got %v", st.Radius[0])
+	}
+
+	lifetime.update(em, 0.6)
+	if st.Len() != 0 {
+		t.Errorf("expired store particle should be killed, Len() = %d", st.Len())
+	}
+}
+
+// benchmarkFrame runs gravity, physics, lifetime and color for n particles
+// stored either as ECS entities in em or in a ParticleStore, optionally
+// spread over a worker pool.
+func benchmarkFrame(b *testing.B, em ecs.EntityManager, n int, useStore, parallel bool) {
+	for i := 0; i < 2; i++ {
+		em.Add(ecs.NewEntity("", []ecs.Component{
+			components.NewPosition().With(400+float32(i)*400, 360),
+			components.NewMass().WithValue(500),
+			components.NewAttractor(),
+		}))
+	}
+
+	emitter := NewEmitterSystem(0, n, 1280, 720)
+	emitter.MinTTL, emitter.MaxTTL = 1e6, 1e6
+	gravity := NewGravitySystem()
+	physics := NewPhysicsSystem(0.99, 500, 1280, 720)
+	lifetime := NewLifetimeSystem()
+	color := NewColorSystem()
+	if useStore {
+		AttachStore(NewParticleStore(n), emitter, gravity, physics, lifetime, color)
+	}
+	if parallel {
+		workers := NewWorkerPool(0)
+		defer workers.Close()
+		AttachWorkers(workers, gravity, physics, color)
+	}
+	for i := 0; i < n; i++ {
+		emitter.spawnParticle(em)
+	}
+
+	const dt = float32(1.0 / 60)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gravity.Process(em)
+		physics.update(em, dt)
+		lifetime.update(em, dt)
+		color.Process(em)
+	}
+}
+
+// BenchmarkFrame_ECS_10k measures a frame with 10k particle entities.
+func BenchmarkFrame_ECS_10k(b *testing.B) {
+	benchmarkFrame(b, ecs.NewEntityManager(), 10000, false, false)
+}
+
+// BenchmarkFrame_Store_10k measures a frame with 10k store particles.
+func BenchmarkFrame_Store_10k(b *testing.B) {
+	benchmarkFrame(b, ecs.NewEntityManager(), 10000, true, false)
+}
+
+// BenchmarkFrame_ECS_50k measures a frame with 50k particle entities.
+func BenchmarkFrame_ECS_50k(b *testing.B) {
+	benchmarkFrame(b, ecs.NewEntityManager(), 50000, false, false)
+}
+
+// BenchmarkFrame_Store_50k measures a frame with 50k store particles.
+func BenchmarkFrame_Store_50k(b *testing.B) {
+	benchmarkFrame(b, ecs.NewEntityManager(), 50000, true, false)
+}
+
+// BenchmarkFrame_ECS_20k measures a frame at the slider maximum.
+func BenchmarkFrame_ECS_20k(b *testing.B) {
+	benchmarkFrame(b, ecs.NewEntityManager(), 20000, false, false)
+}
+
+// BenchmarkFrame_ECSParallel_20k measures a frame at the slider maximum
+// spread over GOMAXPROCS workers.
+func BenchmarkFrame_ECSParallel_20k(b *testing.B) {
+	benchmarkFrame(b, ecs.NewEntityManager(), 20000, false, true)
+}
+
+// BenchmarkFrame_StoreParallel_50k measures a frame with 50k store
+// particles spread over GOMAXPROCS workers.
+func BenchmarkFrame_StoreParallel_50k(b *testing.B) {
+	benchmarkFrame(b, ecs.NewEntityManager(), 50000, true, true)
+}
+
+// TestWorkerPool_For tests that every index is visited exactly once.
+func TestWorkerPool_For(t *testing.T) {
+	pool := NewWorkerPool(4)
+	defer pool.Close()
+
+	for _, n := range []int{0, 10, minParallelChunk*2 + 1, 10000} {
+		visits := make([]int32, n)
+		pool.For(n, func(lo, hi int) {
+			for i := lo; i < hi; i++ {
+				visits[i]++
+			}
+		})
+		for i, v := range visits {
+			if v != 1 {
+				t.Fatalf("n=%d: index %d visited %d times", n, i, v)
+			}
+		}
+	}
+}
+
+// TestWorkerPool_Nil tests that a nil pool runs sequentially.
+func TestWorkerPool_Nil(t *testing.T) {
+	var pool *WorkerPool
+	if pool.Workers() != 1 {
+		t.Errorf("Workers() = %d, want 1", pool.Workers())
+	}
+	calls := 0
+	pool.For(5000, func(lo, hi int) {
+		calls++
+		if lo != 0 || hi != 5000 {
+			t.Errorf("range = [%d, %d), want [0, 5000)", lo, hi)
+		}
+	})
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+// TestPhysicsSystem_ParallelMatchesSequential tests that parallel
+// integration produces the same positions as the sequential path.
+func TestPhysicsSystem_ParallelMatchesSequential(t *testing.T) {
+	const n = 4000
+	run := func(pool *WorkerPool) []*components.Position {
+		em := ecs.NewEntityManager()
+		positions := make([]*components.Position, n)
+		for i := 0; i < n; i++ {
+			positions[i] = components.NewPosition().With(float32(i%1280), float32(i%720))
+			em.Add(ecs.NewEntity("", []ecs.Component{
+				positions[i],
+				components.NewVelocity().With(float32(i%50), -float32(i%30)),
+				components.NewAcceleration().WithX(10),
+			}))
+		}
+		sys := NewPhysicsSystem(0.99, 500, 1280, 720)
+		sys.SetParallel(pool)
+		sys.update(em, 1.0/60)
+		return positions
+	}
+
+	pool := NewWorkerPool(4)
+	defer pool.Close()
+	seq, par := run(nil), run(pool)
+	for i := range seq {
+		if *seq[i] != *par[i] {
+			t.Fatalf("particle %d: sequential %v, parallel %v", i, *seq[i], *par[i])
+		}
+	}
+}
+
+// BenchmarkFrame_QueryCache_10k measures a frame with 10k particle entities
+// held by a QueryCache.
+func BenchmarkFrame_QueryCache_10k(b *testing.B) {
+	benchmarkFrame(b, NewQueryCache(), 10000, false, false)
+}
+
+// BenchmarkCountParticles_FilterByMask measures counting 10k particles by
+// filtering.
+func BenchmarkCountParticles_FilterByMask(b *testing.B) {
+	benchmarkCountParticles(b, ecs.NewEntityManager())
+}
+
+// BenchmarkCountParticles_QueryCache measures counting 10k particles with
+// a cached query.
+func BenchmarkCountParticles_QueryCache(b *testing.B) {
+	benchmarkCountParticles(b, NewQueryCache())
+}
+
+func benchmarkCountParticles(b *testing.B, em ecs.EntityManager) {
+	pool := NewParticlePool()
+	for i := 0; i < 10000; i++ {
+		em.Add(pool.Acquire().Entity)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = countParticles(em)
+	}
+}
+
+// TestQueryCache_AddRemove tests that cached queries follow Add and Remove.
+func TestQueryCache_AddRemove(t *testing.T) {
+	qc := NewQueryCache()
+	pool := NewParticlePool()
+	a, b := pool.Acquire().Entity, pool.Acquire().Entity
+	qc.Add(a)
+
+	if qc.Count(components.MaskParticle) != 1 {
+		t.Fatalf("Count = %d, want 1", qc.Count(components.MaskParticle))
+	}
+
+	// Added after the query was cached
+	qc.Add(b)
+	attractor := ecs.NewEntity("attractor", []ecs.Component{components.NewPosition(), components.NewAttractor()})
+	qc.Add(attractor)
+	if qc.Count(components.MaskParticle) != 2 {
+		t.Errorf("Count = %d, want 2", qc.Count(components.MaskParticle))
+	}
+	if qc.Count(components.MaskPosition) != 3 {
+		t.Errorf("Count(Position) = %d, want 3", qc.Count(components.MaskPosition))
+	}
+
+	qc.Remove(a)
+	if got := qc.Query(components.MaskParticle); len(got) != 1 || got[0] != b {
+		t.Errorf("Query after Remove = %v, want [b]", got)
+	}
+	if len(qc.Entities()) != 2 {
+		t.Errorf("Entities() = %d, want 2", len(qc.Entities()))
+	}
+
+	// Removal by Id for entities not held by identity
+	qc.Remove(&ecs.Entity{Id: "attractor"})
+	if qc.Get("attractor") != nil {
+		t.Error("attractor should be removed by Id")
+	}
+	if qc.Count(components.MaskPosition) != 1 {
+		t.Errorf("Count(Position) = %d, want 1", qc.Count(components.MaskPosition))
+	}
+}
+
+// TestQueryCache_FilterByMaskCopies tests that removing entities while
+// iterating FilterByMask results is safe.
+func TestQueryCache_FilterByMaskCopies(t *testing.T) {
+	qc := NewQueryCache()
+	pool := NewParticlePool()
+	for i := 0; i < 5; i++ {
+		qc.Add(pool.Acquire().Entity)
+	}
+	for _, e := range qc.FilterByMask(components.MaskParticle) {
+		qc.Remove(e)
+	}
+	if qc.Count(components.MaskParticle) != 0 {
+		t.Errorf("Count = %d, want 0", qc.Count(components.MaskParticle))
+	}
+}
+
+// TestQueryCache_Refresh tests that changed components are re-indexed.
+func TestQueryCache_Refresh(t *testing.T) {
+	qc := NewQueryCache()
+	e := NewParticlePool().Acquire().Entity
+	qc.Add(e)
+	if qc.Count(components.MaskTarget) != 0 {
+		t.Fatal("expected no targets")
+	}
+
+	e.Add(components.NewTarget())
+	qc.Refresh(e)
+	if qc.Count(components.MaskTarget) != 1 {
+		t.Errorf("Count(Target) = %d, want 1 after Refresh", qc.Count(components.MaskTarget))
+	}
+
+	e.Remove(components.MaskTarget)
+	qc.Refresh(e)
+	if qc.Count(components.MaskTarget) != 0 {
+		t.Errorf("Count(Target) = %d, want 0 after Refresh", qc.Count(components.MaskTarget))
+	}
+}
+
+// TestLifetimeSystem_QueryCache tests aging and removal with a QueryCache.
+func TestLifetimeSystem_QueryCache(t *testing.T) {
+	qc := NewQueryCache()
+	emitter := NewEmitterSystem(0, 100, 1280, 720)
+	emitter.MinTTL, emitter.MaxTTL = 1, 1
+	for i := 0; i < 10; i++ {
+		emitter.spawnParticle(qc)
+	}
+	lifetime := NewLifetimeSystem()
+	lifetime.SetPool(emitter.GetPool())
+
+	lifetime.update(qc, 2)
+	if countParticles(qc) != 0 {
+		t.Errorf("countParticles = %d, want 0", countParticles(qc))
+	}
+	if emitter.GetPool().Free() != 10 {
+		t.Errorf("Free() = %d, want 10", emitter.GetPool().Free())
+	}
+}
+
+// TestCommandBuffer_Flush tests that recorded commands are applied in order
+// only when flushed.
+func TestCommandBuffer_Flush(t *testing.T) {
+	em := NewQueryCache()
+	buf := NewCommandBuffer()
+	e := NewParticlePool().Acquire().Entity
+
+	buf.Add(e)
+	buf.AddComponent(e, components.NewTarget())
+	if len(em.Entities()) != 0 {
+		t.Fatal("Add should be deferred until Flush")
+	}
+	if buf.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", buf.Len())
+	}
+
+	buf.Flush(em)
+	if em.Count(components.MaskTarget) != 1 {
+		t.Errorf("Count(Target) = %d, want 1", em.Count(components.MaskTarget))
+	}
+
+	buf.RemoveComponent(e, components.MaskTarget)
+	buf.Remove(e)
+	buf.Flush(em)
+	if len(em.Entities()) != 0 {
+		t.Errorf("expected entity to be removed, got %d", len(em.Entities()))
+	}
+	if e.Get(components.MaskTarget) != nil {
+		t.Error("Target should be removed")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Len() after Flush = %d, want 0", buf.Len())
+	}
+}
+
+// TestCommandBuffer_RecyclesOnce tests that a particle removed twice is
+// returned to the pool once.
+func TestCommandBuffer_RecyclesOnce(t *testing.T) {
+	em := ecs.NewEntityManager()
+	pool := NewParticlePool()
+	buf := NewCommandBuffer()
+	buf.SetPool(pool)
+
+	e := pool.Acquire().Entity
+	em.Add(e)
+	buf.Remove(e)
+	buf.Remove(e)
+	buf.Flush(em)
+
+	if pool.Free() != 1 {
+		t.Errorf("Free() = %d, want 1", pool.Free())
+	}
+}
+
+// TestRemoveEntity_SharedIds tests that an entity is not reported as
+// removed when the default manager removed another entity with its Id.
+func TestRemoveEntity_SharedIds(t *testing.T) {
+	em := ecs.NewEntityManager()
+	first := ecs.NewEntity("", []ecs.Component{components.NewPosition()})
+	second := ecs.NewEntity("", []ecs.Component{components.NewPosition()})
+	em.Add(first, second)
+
+	if removeEntity(em, second) {
+		t.Error("removeEntity should report false when another entity was removed")
+	}
+	if !removeEntity(em, second) {
+		t.Error("removeEntity should remove the last entity with the Id")
+	}
+}
+
+// TestCommandBuffer_Deferred tests that the deferred view records Add and
+// Remove while reads go to the entity manager.
+func TestCommandBuffer_Deferred(t *testing.T) {
+	em := NewQueryCache()
+	buf := NewCommandBuffer()
+	existing := NewParticlePool().Acquire().Entity
+	em.Add(existing)
+
+	view := buf.Deferred(em)
+	view.Add(NewParticlePool().Acquire().Entity)
+	view.Remove(existing)
+	if got := len(view.FilterByMask(components.MaskParticle)); got != 1 {
+		t.Errorf("view should read the unchanged world, got %d particles", got)
+	}
+
+	buf.Flush(em)
+	if got := em.Count(components.MaskParticle); got != 1 {
+		t.Errorf("Count = %d, want 1", got)
+	}
+	if em.Query(components.MaskParticle)[0] == existing {
+		t.Error("existing entity should have been removed")
+	}
+}
+
+// TestLifetimeSystem_Commands tests that removals are deferred to the
+// flush system and recycled by the buffer.
+func TestLifetimeSystem_Commands(t *testing.T) {
+	em := NewQueryCache()
+	emitter := NewEmitterSystem(0, 100, 1280, 720)
+	emitter.MinTTL, emitter.MaxTTL = 1, 1
+	buf := NewCommandBuffer()
+	buf.SetPool(emitter.GetPool())
+	lifetime := NewLifetimeSystem()
+	lifetime.SetCommands(buf)
+
+	for i := 0; i < 3; i++ {
+		emitter.spawnParticle(em)
+	}
+	lifetime.update(em, 2)
+	if countParticles(em) != 3 {
+		t.Fatalf("removal should be deferred, got %d particles", countParticles(em))
+	}
+
+	NewCommandFlushSystem(buf).Process(em)
+	if countParticles(em) != 0 {
+		t.Errorf("countParticles = %d, want 0 after flush", countParticles(em))
+	}
+	if emitter.GetPool().Free() != 3 {
+		t.Errorf("Free() = %d, want 3", emitter.GetPool().Free())
+	}
+}
+
+// TestCommandBuffer_ConcurrentRecording tests recording from worker
+// goroutines.
+func TestCommandBuffer_ConcurrentRecording(t *testing.T) {
+	em := NewQueryCache()
+	buf := NewCommandBuffer()
+	pool := NewParticlePool()
+	entities := make([]*ecs.Entity, 4000)
+	for i := range entities {
+		entities[i] = pool.Acquire().Entity
+	}
+
+	workers := NewWorkerPool(4)
+	defer workers.Close()
+	workers.For(len(entities), func(lo, hi int) {
+		for _, e := range entities[lo:hi] {
+			buf.Add(e)
+		}
+	})
+
+	buf.Flush(em)
+	if got := em.Count(components.MaskParticle); got != len(entities) {
+		t.Errorf("Count = %d, want %d", got, len(entities))
+	}
+}
+
+// TestColorSystem_Gradient tests that gradients override linear colors.
+func TestColorSystem_Gradient(t *testing.T) {
+	em := ecs.NewEntityManager()
+	col := components.NewColor().WithGradient(0, 0, 0, 255, 0, 0, 0, 255)
+	life := components.NewLifetime().WithTTL(1)
+	life.Age = 0.5
+	grad := components.NewGradient().
+		WithStop(0, 255, 255, 255, 255).
+		WithStop(0.5, 255, 0, 0, 255).
+		WithStop(1, 0, 0, 0, 0)
+	em.Add(ecs.NewEntity("", []ecs.Component{col, life, grad}))
+
+	NewColorSystem().Process(em)
+
+	if col.R != 255 || col.G != 0 || col.B != 0 || col.A != 255 {
+		t.Errorf("color = (%d, %d, %d, %d), want (255, 0, 0, 255)", col.R, col.G, col.B, col.A)
+	}
+}
+
+// TestEmitterSystem_Gradient tests that spawned particles share the
+// emitter gradient and lose it when recycled.
+func TestEmitterSystem_Gradient(t *testing.T) {
+	em := NewQueryCache()
+	sys := NewEmitterSystem(0, 100, 1280, 720)
+	grad := components.NewGradient().WithStop(0, 1, 2, 3, 4)
+	sys.SetGradient(grad)
+
+	sys.spawnParticle(em)
+	if em.Count(components.MaskGradient) != 1 {
+		t.Fatalf("Count(Gradient) = %d, want 1", em.Count(components.MaskGradient))
+	}
+	e := em.Query(components.MaskGradient)[0]
+	if e.Get(components.MaskGradient) != grad {
+		t.Error("particle should share the emitter gradient")
+	}
+
+	em.Remove(e)
+	sys.GetPool().Release(e)
+	sys.SetGradient(nil)
+	sys.spawnParticle(em)
+	if em.Count(components.MaskGradient) != 0 {
+		t.Error("recycled particle should not keep the gradient")
+	}
+}
+
+// TestColorSystem_ColorSpace tests that colors interpolate in their space.
+func TestColorSystem_ColorSpace(t *testing.T) {
+	em := ecs.NewEntityManager()
+	col := components.NewColor().
+		WithGradient(255, 0, 255, 255, 0, 255, 255, 255).
+		WithSpace(components.SpaceOKLab)
+	life := components.NewLifetime().WithTTL(1)
+	life.Age = 0.5
+	em.Add(ecs.NewEntity("", []ecs.Component{col, life}))
+
+	NewColorSystem().Process(em)
+
+	wantR, wantG, wantB, _ := components.SpaceOKLab.Mix(255, 0, 255, 255, 0, 255, 255, 255, 0.5)
+	if col.R != wantR || col.G != wantG || col.B != wantB {
+		t.Errorf("color = (%d, %d, %d), want OKLab midpoint (%d, %d, %d)", col.R, col.G, col.B, wantR, wantG, wantB)
+	}
+}
+
+// TestColorSystem_OverLifeCurves tests that size and alpha follow their
+// curves instead of the linear interpolation.
+func TestColorSystem_OverLifeCurves(t *testing.T) {
+	em := ecs.NewEntityManager()
+	col := components.NewColor().WithGradient(255, 255, 255, 200, 255, 255, 255, 0)
+	size := components.NewSize().WithRadius(10).WithEndSize(0)
+	life := components.NewLifetime().WithTTL(1)
+	life.Age = 0.5
+	em.Add(ecs.NewEntity("", []ecs.Component{
+		col, size, life,
+		components.NewSizeOverLife(curves.Keys(0, 1, 1, 2)),
+		components.NewAlphaOverLife(curves.FadeOutQuad),
+	}))
+
+	NewColorSystem().Process(em)
+
+	if size.Radius < 14.99 || size.Radius > 15.01 {
+		t.Errorf("Radius = %v, want 15", size.Radius)
+	}
+	if col.A != 150 {
+		t.Errorf("A = %d, want 150", col.A)
+	}
+}
+
+// TestColorSystem_GradientAlphaCurve tests that an alpha curve scales the
+// alpha of a gradient instead of replacing it.
+func TestColorSystem_GradientAlphaCurve(t *testing.T) {
+	em := ecs.NewEntityManager()
+	col := components.NewColor().WithGradient(255, 255, 255, 255, 255, 255, 255, 255)
+	life := components.NewLifetime().WithTTL(1)
+	life.Age = 0.5
+	em.Add(ecs.NewEntity("", []ecs.Component{
+		col, life,
+		components.NewGradient().WithStop(0, 255, 0, 0, 200).WithStop(1, 255, 0, 0, 200),
+		components.NewAlphaOverLife(curves.FadeOutQuad),
+	}))
+
+	NewColorSystem().Process(em)
+
+	if col.R != 255 || col.G != 0 || col.A != 150 {
+		t.Errorf("color = (%d, %d, %d, %d), want gradient red at alpha 200 × 0.75", col.R, col.G, col.B, col.A)
+	}
+}
+
+// TestPhysicsSystem_SpeedOverLife tests that the curve scales the
+// displacement but leaves the velocity itself untouched.
+func TestPhysicsSystem_SpeedOverLife(t *testing.T) {
+	em := ecs.NewEntityManager()
+	pos := components.NewPosition().With(100, 100)
+	vel := components.NewVelocity().WithX(100)
+	life := components.NewLifetime().WithTTL(1)
+	life.Age = 0.5
+	em.Add(ecs.NewEntity("", []ecs.Component{
+		pos, vel, life,
+		components.NewSpeedOverLife(curves.Range(1, 0, curves.Linear)),
+	}))
+
+	NewPhysicsSystem(1, 1000, 1280, 720).update(em, 0.1)
+
+	if pos.X < 104.99 || pos.X > 105.01 {
+		t.Errorf("X = %v, want 105", pos.X)
+	}
+	if vel.X != 100 {
+		t.Errorf("velocity X = %v, want 100", vel.X)
+	}
+}
+
+// TestEmitterSystem_OverLifeCurves tests that spawned particles share the
+// emitter's over-life components.
+func TestEmitterSystem_OverLifeCurves(t *testing.T) {
+	em := NewQueryCache()
+	sys := NewEmitterSystem(0, 100, 1280, 720)
+	sys.SetOverLifeCurves(curves.Linear, nil, curves.OutQuad)
+
+	sys.spawnParticle(em)
+	sys.spawnParticle(em)
+	if n := em.Count(components.MaskSizeOverLife); n != 2 {
+		t.Errorf("Count(SizeOverLife) = %d, want 2", n)
+	}
+	if n := em.Count(components.MaskAlphaOverLife); n != 0 {
+		t.Errorf("Count(AlphaOverLife) = %d, want 0", n)
+	}
+	if n := em.Count(components.MaskSpeedOverLife); n != 2 {
+		t.Errorf("Count(SpeedOverLife) = %d, want 2", n)
+	}
+}
+
+// TestTrailRenderer_ApplyQuality tests that the trail length follows the
+// quality level.
+func TestTrailRenderer_ApplyQuality(t *testing.T) {
+	r := NewTrailRenderer(0, TrailLines)
+	if r.IsEnabled() {
+		t.Error("zero-length renderer should be disabled")
+	}
+
+	for _, level := range []premium.QualityLevel{premium.QualityLow, premium.QualityMedium, premium.QualityHigh} {
+		q := premium.GetQualitySettings(level)
+		r.ApplyQuality(q)
+		if r.Length() != q.TrailLength || r.IsEnabled() != (q.TrailLength > 0) {
+			t.Errorf("%s: length = %d, enabled = %v", level, r.Length(), r.IsEnabled())
+		}
+	}
+}
+
+// TestRenderSystem_QualityChange tests that user quality changes reach the
+// trail renderer and the callback.
+func TestRenderSystem_QualityChange(t *testing.T) {
+	sys := NewRenderSystem(800, 600, "test")
+	var got premium.QualityLevel = -1
+	sys.SetOnQualityChange(func(level premium.QualityLevel) { got = level })
+
+	sys.changeQuality(premium.QualityLow)
+	if got != premium.QualityLow {
+		t.Errorf("callback level = %v, want Low", got)
+	}
+	if sys.trails.IsEnabled() {
+		t.Error("Low quality should disable trails")
+	}
+	sys.SetQuality(premium.QualityHigh)
+	if sys.trails.Length() != 5 {
+		t.Errorf("trail length = %d, want 5", sys.trails.Length())
+	}
+}
+
+// TestPhysicsSystem_Trail tests that trails record positions and are
+// cleared when the particle wraps around an edge.
+func TestPhysicsSystem_Trail(t *testing.T) {
+	em := ecs.NewEntityManager()
+	pos := components.NewPosition().With(100, 100)
+	trail := components.NewTrail(3)
+	em.Add(ecs.NewEntity("", []ecs.Component{pos, components.NewVelocity().WithX(100), trail}))
+	sys := NewPhysicsSystem(1, 1000, 200, 200)
+
+	sys.update(em, 0.1)
+	sys.update(em, 0.1)
+	if trail.Len() != 2 {
+		t.Fatalf("trail.Len() = %d, want 2", trail.Len())
+	}
+	if x, _ := trail.At(0); x < 109.99 || x > 110.01 {
+		t.Errorf("newest trail x = %v, want 110", x)
+	}
+
+	for i := 0; i < 10 && trail.Len() > 0; i++ {
+		sys.update(em, 0.1)
+	}
+	if trail.Len() != 0 {
+		t.Error("trail should be cleared when wrapping")
+	}
+}
+
+// TestEmitterSystem_Trails tests that spawned particles get trails of the
+// quality's length and that trails are recycled through the pool.
+func TestEmitterSystem_Trails(t *testing.T) {
+	em := NewQueryCache()
+	sys := NewEmitterSystem(0, 100, 1280, 720)
+	sys.SetQuality(premium.QualityHigh)
+
+	sys.spawnParticle(em)
+	e := em.Query(components.MaskTrail)[0]
+	trail := e.Get(components.MaskTrail).(*components.Trail)
+	if trail.Cap() != 5 {
+		t.Errorf("trail.Cap() = %d, want 5", trail.Cap())
+	}
+
+	em.Remove(e)
+	sys.GetPool().Release(e)
+	sys.SetQuality(premium.QualityMedium)
+	sys.spawnParticle(em)
+	if got := em.Query(components.MaskTrail)[0].Get(components.MaskTrail); got != trail {
+		t.Error("released trail should be reused")
+	}
+	if trail.Cap() != 3 {
+		t.Errorf("reused trail.Cap() = %d, want 3", trail.Cap())
+	}
+
+	sys.SetQuality(premium.QualityLow)
+	sys.spawnParticle(em)
+	if n := em.Count(components.MaskTrail); n != 1 {
+		t.Errorf("Count(Trail) = %d, want 1", n)
+	}
+}
+
+// TestRenderSystem_Passes tests that the pass renderers follow the quality
+// level and the palette.
+func TestRenderSystem_Passes(t *testing.T) {
+	sys := NewRenderSystem(800, 600, "test")
+	if sys.Passes() != AllPasses {
+		t.Errorf("Passes() = %b, want all passes", sys.Passes())
+	}
+
+	sys.SetQuality(premium.QualityHigh)
+	high := premium.GetQualitySettings(premium.QualityHigh)
+	if !sys.glow.IsEnabled() || sys.glow.passes != high.GlowPasses {
+		t.Errorf("glow enabled = %v, passes = %d, want true, %d", sys.glow.IsEnabled(), sys.glow.passes, high.GlowPasses)
+	}
+	if sys.blur.enabled != high.MotionBlur || sys.blur.samples != high.BlurSamples {
+		t.Errorf("blur enabled = %v, samples = %d, want %v, %d", sys.blur.enabled, sys.blur.samples, high.MotionBlur, high.BlurSamples)
+	}
+
+	sys.SetQuality(premium.QualityLow)
+	if sys.glow.IsEnabled() || sys.blur.enabled {
+		t.Error("Low quality should disable glow and motion blur")
+	}
+
+	sys.SetPalette(premium.FireworkPalette)
+	if sys.glow.palette.Name != premium.FireworkPalette.Name {
+		t.Errorf("glow palette = %s, want %s", sys.glow.palette.Name, premium.FireworkPalette.Name)
+	}
+
+	sys.SetPasses(PassGlow)
+	if sys.Passes()&PassMotionBlur != 0 {
+		t.Error("SetPasses should replace the pass set")
+	}
+}
+
+// TestBloomRenderer_ApplyQuality tests that radius and downsample follow
+// the quality level and that bloom stays inactive until loaded.
+func TestBloomRenderer_ApplyQuality(t *testing.T) {
+	r := NewBloomRenderer(1280, 720)
+
+	high := premium.GetQualitySettings(premium.QualityHigh)
+	r.ApplyQuality(high)
+	if !r.IsEnabled() || r.radius != high.BloomRadius {
+		t.Errorf("enabled = %v, radius = %v, want true, %v", r.IsEnabled(), r.radius, high.BloomRadius)
+	}
+	if w, h := r.bufferSize(); w != 1280/int32(high.BloomDownsample) || h != 720/int32(high.BloomDownsample) {
+		t.Errorf("bufferSize() = %d×%d, want downsampled by %d", w, h, high.BloomDownsample)
+	}
+	if r.Active() {
+		t.Error("bloom should be inactive before Load")
+	}
+
+	r.ApplyQuality(premium.GetQualitySettings(premium.QualityLow))
+	if r.IsEnabled() {
+		t.Error("Low quality should disable bloom")
+	}
+}
+
+// TestPremultiply tests the color conversion used for screen blending.
+func TestPremultiply(t *testing.T) {
+	r, g, b, a := premultiply(255, 128, 0, 128)
+	if r != 128 || g != 64 || b != 0 || a != 255 {
+		t.Errorf("premultiply = (%d, %d, %d, %d), want (128, 64, 0, 255)", r, g, b, a)
+	}
+}
+
+// TestRenderSystem_BlendMode tests the default blend mode setting.
+func TestRenderSystem_BlendMode(t *testing.T) {
+	sys := NewRenderSystem(800, 600, "test")
+	if sys.BlendMode() != components.BlendAlpha {
+		t.Errorf("default BlendMode() = %s, want Alpha", sys.BlendMode())
+	}
+	sys.SetBlendMode(components.BlendAdditive)
+	if sys.BlendMode() != components.BlendAdditive {
+		t.Errorf("BlendMode() = %s, want Additive", sys.BlendMode())
+	}
+}
+
+// TestSpriteSheet_FrameRect tests the frame grid of a sprite sheet.
+func TestSpriteSheet_FrameRect(t *testing.T) {
+	sheet := NewSpriteSheet(image.NewNRGBA(image.Rect(0, 0, 96, 64)), 32, 32)
+	if sheet.Frames() != 6 {
+		t.Fatalf("Frames() = %d, want 6", sheet.Frames())
+	}
+	tests := []struct {
+		frame int
+		x, y  float32
+	}{
+		{0, 0, 0}, {2, 64, 0}, {4, 32, 32}, {7, 32, 0}, {-1, 64, 32},
+	}
+	for _, tt := range tests {
+		r := sheet.FrameRect(tt.frame)
+		if r.X != tt.x || r.Y != tt.y || r.Width != 32 || r.Height != 32 {
+			t.Errorf("FrameRect(%d) = %+v, want (%v, %v, 32, 32)", tt.frame, r, tt.x, tt.y)
+		}
+	}
+
+	single := NewSpriteSheet(image.NewNRGBA(image.Rect(0, 0, 16, 16)), 64, 64)
+	if single.Frames() != 1 || single.FrameRect(0).Width != 16 {
+		t.Errorf("oversized frames should clamp to the image, got %d frames", single.Frames())
+	}
+}
+
+// TestLoadSpriteSheet tests loading PNG atlases.
+func TestLoadSpriteSheet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "atlas.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, image.NewNRGBA(image.Rect(0, 0, 64, 16))); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	sheet, err := LoadSpriteSheet(path, 16, 16)
+	if err != nil {
+		t.Fatalf("LoadSpriteSheet() error = %v", err)
+	}
+	if sheet.Frames() != 4 {
+		t.Errorf("Frames() = %d, want 4", sheet.Frames())
+	}
+
+	if _, err := LoadSpriteSheet(path, 32, 32); err == nil {
+		t.Error("frames larger than the image should fail")
+	}
+	if _, err := LoadSpriteSheet(filepath.Join(t.TempDir(), "missing.png"), 16, 16); err == nil {
+		t.Error("missing file should fail")
+	}
+}
+
+// TestPhysicsSystem_AngularVelocity tests that rotation advances and wraps.
+func TestPhysicsSystem_AngularVelocity(t *testing.T) {
+	em := ecs.NewEntityManager()
+	rot := components.NewRotation().WithAngle(350)
+	em.Add(ecs.NewEntity("", []ecs.Component{
+		components.NewPosition(), components.NewVelocity(),
+		rot, components.NewAngularVelocity().WithSpeed(200),
+	}))
+
+	NewPhysicsSystem(1, 1000, 1280, 720).update(em, 0.1)
+	if rot.Angle < 9.99 || rot.Angle > 10.01 {
+		t.Errorf("Angle = %v, want 10", rot.Angle)
+	}
+}
+
+// TestEmitterSystem_Sprite tests that spawned particles share the sprite
+// and get recycled spin components.
+func TestEmitterSystem_Sprite(t *testing.T) {
+	em := NewQueryCache()
+	sys := NewEmitterSystem(0, 100, 1280, 720)
+	sprite := components.NewSprite("spark")
+	sys.SetSprite(sprite, 90)
+
+	sys.spawnParticle(em)
+	e := em.Query(components.MaskSprite)[0]
+	if e.Get(components.MaskSprite) != sprite {
+		t.Error("particle should share the emitter sprite")
+	}
+	av := e.Get(components.MaskAngularVelocity).(*components.AngularVelocity)
+	if av.Speed < -90 || av.Speed > 90 {
+		t.Errorf("spin = %v, want within ±90", av.Speed)
+	}
+
+	em.Remove(e)
+	sys.GetPool().Release(e)
+	sys.spawnParticle(em)
+	if em.Query(components.MaskAngularVelocity)[0].Get(components.MaskAngularVelocity) != av {
+		t.Error("released spin components should be reused")
+	}
+
+	sys.SetSprite(nil, 0)
+	sys.spawnParticle(em)
+	if n := em.Count(components.MaskSprite); n != 1 {
+		t.Errorf("Count(Sprite) = %d, want 1", n)
+	}
+}
+
+// TestQuadBatch_Add tests quad corners and texture coordinates.
+func TestQuadBatch_Add(t *testing.T) {
+	sheet := NewSpriteSheet(image.NewNRGBA(image.Rect(0, 0, 64, 32)), 32, 32)
+	q := NewQuadBatch(sheet)
+
+	q.Add(100, 50, 20, 10, 0, sheet.FrameRect(1), 1, 2, 3, 4)
+	want := []float32{90, 45, 90, 55, 110, 55, 110, 45}
+	for i, v := range want {
+		if q.positions[i] != v {
+			t.Fatalf("positions = %v, want %v", q.positions, want)
+		}
+	}
+	wantUV := []float32{0.5, 0, 0.5, 1, 1, 1, 1, 0}
+	for i, v := range wantUV {
+		if q.texcoords[i] != v {
+			t.Fatalf("texcoords = %v, want %v", q.texcoords, wantUV)
+		}
+	}
+
+	// A quarter turn maps the top left corner to the top right
+	q.Add(0, 0, 2, 2, 90, sheet.FrameRect(0), 255, 255, 255, 255)
+	if x, y := q.positions[8], q.positions[9]; x < 0.999 || x > 1.001 || y < -1.001 || y > -0.999 {
+		t.Errorf("rotated corner = (%v, %v), want (1, -1)", x, y)
+	}
+	if q.Len() != 2 || q.colors[3] != 4 {
+		t.Errorf("Len() = %d, colors = %v", q.Len(), q.colors)
+	}
+
+	q.Reset()
+	if q.Len() != 0 {
+		t.Errorf("Len() after Reset = %d, want 0", q.Len())
+	}
+}
+
+// TestBatchRenderer_Queue tests that shapes are queued in runs sharing a
+// texture, in the order they were added.
+func TestBatchRenderer_Queue(t *testing.T) {
+	r := NewBatchRenderer()
+	spark := NewSpriteSheet(image.NewNRGBA(image.Rect(0, 0, 64, 16)), 16, 16)
+	smoke := NewSpriteSheet(image.NewNRGBA(image.Rect(0, 0, 16, 16)), 16, 16)
+
+	r.Circle(10, 10, 5, 255, 0, 0, 255)
+	r.Sprite(spark, 2, 20, 20, 8, 45, 255, 255, 255, 255)
+	r.Sprite(spark, 1, 25, 25, 8, 0, 255, 255, 255, 255)
+	r.Sprite(smoke, 0, 30, 30, 8, 0, 255, 255, 255, 255)
+	r.Sprite(spark, 3, 40, 40, 8, 0, 255, 255, 255, 255)
+
+	if r.Len() != 5 {
+		t.Errorf("Len() = %d, want 5", r.Len())
+	}
+	want := []quadRun{
+		{r.circles, 0, 1},
+		{r.sprites[spark], 0, 2},
+		{r.sprites[smoke], 0, 1},
+		{r.sprites[spark], 2, 1},
+	}
+	if !slices.Equal(r.runs, want) {
+		t.Errorf("runs = %v, want circle, spark ×2, smoke, spark", r.runs)
+	}
+	// Circles cover the whole disc texture
+	if q := r.circles; q.texcoords[0] != 0 || q.texcoords[4] != 1 || q.positions[0] != 5 {
+		t.Errorf("circle quad = %v / %v", q.positions, q.texcoords)
+	}
+
+	r.reset()
+	if r.Len() != 0 || r.circles.Len() != 0 || r.sprites[spark].Len() != 0 {
+		t.Error("reset should empty the runs and batches")
+	}
+}
+
+// TestRenderSystem_BatchedZOrder tests that batched sprites and circles of
+// one layer are drawn in Z order, whatever their texture.
+func TestRenderSystem_BatchedZOrder(t *testing.T) {
+	sys := NewRenderSystem(800, 600, "test")
+	sys.SetPasses(0)
+	sys.SetBatched(true)
+	sheet := NewSpriteSheet(image.NewNRGBA(image.Rect(0, 0, 16, 16)), 16, 16)
+	sys.AddSpriteSheet("ember", sheet)
+
+	em := ecs.NewEntityManager()
+	add := func(z float32, extra ...ecs.Component) {
+		em.Add(ecs.NewEntity(components.NewParticleID(), append([]ecs.Component{
+			components.NewPosition(), components.NewColor(), components.NewSize(),
+			components.NewLayer(components.LayerParticles).WithZ(z),
+		}, extra...)))
+	}
+	add(2)
+	add(1, components.NewSprite("ember"))
+	add(0)
+	sys.bucketLayers(em)
+	for _, e := range sys.buckets[components.LayerParticles] {
+		sys.drawEntity(e, 0, 0)
+	}
+
+	var got []*QuadBatch
+	for _, run := range sys.batch.runs {
+		got = append(got, run.batch)
+	}
+	if want := []*QuadBatch{sys.batch.circles, sys.batch.sprites[sheet], sys.batch.circles}; !slices.Equal(got, want) {
+		t.Errorf("runs draw %v, want circle, sprite, circle", got)
+	}
+}
+
+// TestDrawCounter tests the draw call estimate of immediate drawing.
+func TestDrawCounter(t *testing.T) {
+	var c drawCounter
+	perDraw := defaultBatchVertices / circleVertices
+	for i := 0; i < perDraw; i++ {
+		c.add(nil, circleVertices)
+	}
+	if c.draws != 1 {
+		t.Errorf("draws = %d, want 1 for %d circles", c.draws, perDraw)
+	}
+	c.add(nil, circleVertices)
+	if c.draws != 2 {
+		t.Errorf("draws = %d, want 2 after the batch is full", c.draws)
+	}
+
+	sheet := NewSpriteSheet(image.NewNRGBA(image.Rect(0, 0, 8, 8)), 8, 8)
+	c.add(sheet, spriteVertices)
+	c.add(nil, circleVertices)
+	if c.draws != 4 {
+		t.Errorf("draws = %d, want 4 after two texture switches", c.draws)
+	}
+
+	c.reset()
+	if c.draws != 0 {
+		t.Errorf("draws after reset = %d, want 0", c.draws)
+	}
+}
+
+// TestBatchCapacity tests render batch growth and the resulting draws.
+func TestBatchCapacity(t *testing.T) {
+	tests := []struct {
+		current, n, want int
+	}{
+		{0, 100, minBatchQuads},
+		{0, minBatchQuads + 1, 2 * minBatchQuads},
+		{2 * minBatchQuads, 100, 2 * minBatchQuads},
+		{0, 10 * maxBatchQuads, maxBatchQuads},
+	}
+	for _, tt := range tests {
+		if got := batchCapacity(tt.current, tt.n); got != tt.want {
+			t.Errorf("batchCapacity(%d, %d) = %d, want %d", tt.current, tt.n, got, tt.want)
+		}
+	}
+	if got := batchDraws(3*maxBatchQuads-1, maxBatchQuads); got != 3 {
+		t.Errorf("batchDraws() = %d, want 3", got)
+	}
+}
+
+// countingDrawer counts the draw calls of immediate drawing without a
+// window.
+type countingDrawer struct {
+	counter drawCounter
+}
+
+func (d *countingDrawer) Circle(x, y, radius float32, r, g, b, a uint8) {
+	d.counter.add(nil, circleVertices)
+}
+
+func (d *countingDrawer) Sprite(sheet *SpriteSheet, frame int, x, y, radius, angle float32, r, g, b, a uint8) {
+	d.counter.add(sheet, spriteVertices)
+}
+
+// drawTestParticles draws n particles through the glow and motion blur
+// passes with d.
+func drawTestParticles(d particleDrawer, glow *GlowRenderer, blur *MotionBlurRenderer, n int) {
+	for p := 0; p < n; p++ {
+		x, y := float32(p%1280), float32(p/1280*10)
+		glow.draw(d, x, y, 3, 255, 200, 100, 255)
+		blur.draw(d, x, y, 400, 0, 3, 255, 200, 100, 255)
+	}
+}
+
+// highQualityPasses returns the glow and motion blur passes at high
+// quality.
+func highQualityPasses() (*GlowRenderer, *MotionBlurRenderer) {
+	q := premium.GetQualitySettings(premium.QualityHigh)
+	glow := NewGlowRenderer(true, 0)
+	glow.ApplyQuality(q)
+	blur := NewMotionBlurRenderer(true, 0)
+	blur.ApplyQuality(q)
+	return glow, blur
+}
+
+// benchmarkParticleDraw queues n particles through the high quality glow
+// and motion blur passes per iteration and reports the draw calls of a
+// frame. The immediate variant only counts its circles; the batched one
+// builds the vertex buffer of a BatchRenderer, so ns/op is the CPU cost of
+// batching. BenchmarkParticleFrame_* compares frame times.
+func benchmarkParticleDraw(b *testing.B, n int, batched bool) {
+	glow, blur := highQualityPasses()
+	batch := NewBatchRenderer()
+	counting := &countingDrawer{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	var draws int
+	for i := 0; i < b.N; i++ {
+		if !batched {
+			counting.counter.reset()
+			drawTestParticles(counting, glow, blur, n)
+			draws = counting.counter.draws
+			continue
+		}
+		drawTestParticles(batch, glow, blur, n)
+		draws = 0
+		for _, run := range batch.runs {
+			draws += batchDraws(run.count, batchCapacity(0, run.count))
+		}
+		batch.reset()
+	}
+	b.ReportMetric(float64(draws), "draws/frame")
+}
+
+// BenchmarkParticleDraw_Immediate_10k reports the draw calls of 10k
+// particles drawn with one raylib call per circle.
+func BenchmarkParticleDraw_Immediate_10k(b *testing.B) {
+	benchmarkParticleDraw(b, 10000, false)
+}
+
+// BenchmarkParticleDraw_Batched_10k measures building the quad batch for
+// 10k particles and reports its draw calls.
+func BenchmarkParticleDraw_Batched_10k(b *testing.B) {
+	benchmarkParticleDraw(b, 10000, true)
+}
+
+// benchmarkParticleFrame draws frames of n particles through the high
+// quality glow and motion blur passes in a hidden window, so ns/op is the
+// frame time. It is skipped where no window can be opened.
+func benchmarkParticleFrame(b *testing.B, n int, batched bool) {
+	switch {
+	case runtime.GOOS == "darwin":
+		b.Skip("windows must be opened on the main thread")
+	case runtime.GOOS == "linux" && os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "":
+		b.Skip("no display to open a window on")
+	}
+	// The GL context belongs to the thread that opened the window
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	rl.SetTraceLogLevel(rl.LogWarning)
+	rl.SetConfigFlags(rl.FlagWindowHidden)
+	rl.InitWindow(1280, 720, "benchmark")
+	defer rl.CloseWindow()
+
+	glow, blur := highQualityPasses()
+	batch := NewBatchRenderer()
+	defer batch.Unload()
+	direct := &immediateDrawer{}
+
+	b.ResetTimer()
+	var draws int
+	for i := 0; i < b.N; i++ {
+		direct.counter.reset()
+		batch.ResetStats()
+		rl.BeginDrawing()
+		rl.ClearBackground(rl.Black)
+		if batched {
+			drawTestParticles(batch, glow, blur, n)
+			batch.Flush()
+			draws = batch.DrawCalls()
+		} else {
+			drawTestParticles(direct, glow, blur, n)
+			draws = direct.counter.draws
+		}
+		rl.EndDrawing()
+	}
+	b.ReportMetric(float64(draws), "draws/frame")
+}
+
+// BenchmarkParticleFrame_Immediate_10k measures frames of 10k particles
+// drawn with one raylib call per circle.
+func BenchmarkParticleFrame_Immediate_10k(b *testing.B) {
+	benchmarkParticleFrame(b, 10000, false)
+}
+
+// BenchmarkParticleFrame_Batched_10k measures frames of 10k particles
+// drawn as batched quads.
+func BenchmarkParticleFrame_Batched_10k(b *testing.B) {
+	benchmarkParticleFrame(b, 10000, true)
+}
+
+// TestRenderSystem_BucketLayers tests that entities are grouped by layer
+// and ordered by Z.
+func TestRenderSystem_BucketLayers(t *testing.T) {
+	em := ecs.NewEntityManager()
+	add := func(id string, extra ...ecs.Component) {
+		em.Add(ecs.NewEntity(id, append([]ecs.Component{
+			components.NewPosition(), components.NewColor(), components.NewSize(),
+		}, extra...)))
+	}
+	add("particle")
+	add("front-high", components.NewLayer(components.LayerForeground).WithZ(5))
+	add("back", components.NewLayer(components.LayerBackground))
+	add("front-low", components.NewLayer(components.LayerForeground).WithZ(-1))
+	add("front-mid", components.NewLayer(components.LayerForeground))
+	add("invalid", components.NewLayer(components.RenderLayer(42)))
+	em.Add(ecs.NewEntity("attractor", []ecs.Component{
+		components.NewPosition(), components.NewMass().WithValue(100), components.NewAttractor(),
+	}))
+	em.Add(ecs.NewEntity("hidden-attractor", []ecs.Component{
+		components.NewPosition(), components.NewMass().WithValue(100), components.NewAttractor(),
+		components.NewLayer(components.LayerBackground),
+	}))
+
+	sys := NewRenderSystem(800, 600, "test")
+	sys.SetGizmos(true)
+	if n := sys.bucketLayers(em); n != 6 {
+		t.Errorf("bucketLayers() = %d, want 6", n)
+	}
+
+	ids := func(layer components.RenderLayer) []string {
+		var out []string
+		for _, e := range sys.buckets[layer] {
+			out = append(out, e.Id)
+		}
+		return out
+	}
+	want := map[components.RenderLayer][]string{
+		components.LayerBackground: {"back"},
+		components.LayerParticles:  {"particle", "invalid"},
+		components.LayerForeground: {"front-low", "front-mid", "front-high"},
+	}
+	for layer, w := range want {
+		if got := ids(layer); !slices.Equal(got, w) {
+			t.Errorf("%s layer = %v, want %v", layer, got, w)
+		}
+	}
+	if len(sys.gizmos[components.LayerForeground]) != 1 || len(sys.gizmos[components.LayerBackground]) != 1 {
+		t.Errorf("gizmos should default to the foreground and follow their Layer")
+	}
+}
+
+// TestRenderSystem_LayerSettings tests per-layer visibility and blend modes.
+func TestRenderSystem_LayerSettings(t *testing.T) {
+	sys := NewRenderSystem(800, 600, "test")
+	for layer := components.RenderLayer(0); layer < components.LayerCount; layer++ {
+		if !sys.IsLayerVisible(layer) {
+			t.Errorf("%s layer should be visible by default", layer)
+		}
+	}
+
+	sys.SetLayerVisible(components.LayerUI, false)
+	if sys.IsLayerVisible(components.LayerUI) {
+		t.Error("UI layer should be hidden")
+	}
+
+	sys.SetBlendMode(components.BlendAdditive)
+	if sys.LayerBlendMode(components.LayerParticles) != components.BlendAdditive {
+		t.Error("SetBlendMode should set the particles layer's mode")
+	}
+	sys.SetLayerBlendMode(components.LayerForeground, components.BlendMultiply)
+	if sys.LayerBlendMode(components.LayerForeground) != components.BlendMultiply || sys.BlendMode() != components.BlendAdditive {
+		t.Error("layer blend modes should be independent")
+	}
+
+	// Unknown layers are ignored
+	sys.SetLayerVisible(components.RenderLayer(99), false)
+	if sys.IsLayerVisible(components.RenderLayer(99)) || sys.LayerBlendMode(components.RenderLayer(99)) != components.BlendAlpha {
+		t.Error("unknown layers should be invisible and alpha blended")
+	}
+}
+
+// TestCameraSystem_Follow tests that cameras glide towards their target.
+func TestCameraSystem_Follow(t *testing.T) {
+	em := ecs.NewEntityManager()
+	cam := components.NewCamera().WithFollow("leader").WithSmoothing(0)
+	em.Add(ecs.NewEntity("camera", []ecs.Component{cam}))
+	em.Add(ecs.NewEntity("leader", []ecs.Component{components.NewPosition().With(100, 50)}))
+
+	sys := &cameraSystem{}
+	sys.update(em, 0.016)
+	if cam.X != 100 || cam.Y != 50 {
+		t.Errorf("snapping camera at (%v, %v), want (100, 50)", cam.X, cam.Y)
+	}
+
+	cam.With(0, 0).WithSmoothing(4)
+	sys.update(em, 0.1)
+	if cam.X <= 0 || cam.X >= 100 || cam.Y <= 0 || cam.Y >= 50 {
+		t.Errorf("smoothed camera at (%v, %v), want between origin and target", cam.X, cam.Y)
+	}
+
+	// A missing target leaves the camera in place
+	cam.With(7, 7).WithFollow("gone")
+	sys.update(em, 0.1)
+	if cam.X != 7 || cam.Y != 7 {
+		t.Errorf("camera moved to (%v, %v) without a target", cam.X, cam.Y)
+	}
+}
+
+// TestRenderSystem_Camera2D tests the view used for drawing.
+func TestRenderSystem_Camera2D(t *testing.T) {
+	sys := NewRenderSystem(800, 600, "test")
+	em := ecs.NewEntityManager()
+
+	cam := sys.camera2D(em)
+	if cam.Target != cam.Offset || cam.Zoom != 1 {
+		t.Errorf("without a camera the view should cover the window, got %+v", cam)
+	}
+
+	c := components.NewCamera().With(10, 20).WithZoom(2).WithRotation(30)
+	em.Add(ecs.NewEntity("camera", []ecs.Component{c}))
+	sys.ApplyPulse(1.5, 1)
+	cam = sys.camera2D(em)
+	if cam.Target.X != 10 || cam.Target.Y != 20 || cam.Rotation != 30 || cam.Zoom != 3 {
+		t.Errorf("camera2D() = %+v, want target (10, 20), rotation 30, pulsed zoom 3", cam)
+	}
+	// Input maps the cursor through the pulsed zoom the world is drawn with
+	if c.Pulse != 1.5 || c.ViewZoom() != cam.Zoom {
+		t.Errorf("camera Pulse = %v, ViewZoom() = %v, want 1.5 and %v", c.Pulse, c.ViewZoom(), cam.Zoom)
+	}
+}
+
+// TestResize_Bounds tests that physics and emitter follow new bounds.
+func TestResize_Bounds(t *testing.T) {
+	em := ecs.NewEntityManager()
+	pos := components.NewPosition().With(1500, 100)
+	em.Add(ecs.NewEntity("p", []ecs.Component{pos, components.NewVelocity()}))
+
+	physics := NewPhysicsSystem(1, 1000, 1280, 720)
+	physics.SetBounds(1920, 1080)
+	physics.update(em, 0.016)
+	if pos.X != 1500 {
+		t.Errorf("X = %v, particle inside the new bounds should not wrap", pos.X)
+	}
+
+	emitter := NewEmitterSystem(0, 1000, 1280, 720)
+	emitter.SetBounds(100, 50)
+	for i := 0; i < 200; i++ {
+		emitter.spawnParticle(em)
+	}
+	for _, e := range em.FilterByMask(components.MaskParticle) {
+		p := e.Get(components.MaskPosition).(*components.Position)
+		if p.X < 0 || p.X > 100 || p.Y < 0 || p.Y > 50 {
+			t.Fatalf("particle spawned at (%v, %v), outside 100x50", p.X, p.Y)
+		}
+	}
+}
+
+// TestRenderSystem_Resize tests that a new window size reaches the layout,
+// the bloom targets and the callback.
+func TestRenderSystem_Resize(t *testing.T) {
+	sys := NewRenderSystem(1280, 720, "test")
+	var gotW, gotH int32
+	sys.SetOnResize(func(w, h int32) { gotW, gotH = w, h })
+
+	sys.resize(1920, 1080)
+	if gotW != 1920 || gotH != 1080 {
+		t.Errorf("callback got %dx%d, want 1920x1080", gotW, gotH)
+	}
+	if x, y := sys.layout.BottomRight(); x != 1910 || y != 1070 {
+		t.Errorf("layout BottomRight = (%d, %d), want (1910, 1070)", x, y)
+	}
+	if w, h := sys.bloom.bufferSize(); w != 1920/4 || h != 1080/4 {
+		t.Errorf("bloom buffers %dx%d, want %dx%d", w, h, 1920/4, 1080/4)
+	}
+	if cam := sys.camera2D(ecs.NewEntityManager()); cam.Offset.X != 960 || cam.Offset.Y != 540 {
+		t.Errorf("view offset = %v, want the new window center", cam.Offset)
+	}
+}
+
+// TestGenerateStars tests the layering and determinism of starfields.
+func TestGenerateStars(t *testing.T) {
+	stars := generateStars(300, 3)
+	if len(stars) != 300 {
+		t.Fatalf("len = %d, want 300", len(stars))
+	}
+	perDepth := make(map[float32]int)
+	for _, s := range stars {
+		perDepth[s.depth]++
+		if s.x < 0 || s.x >= 1 || s.y < 0 || s.y >= 1 {
+			t.Fatalf("star at (%v, %v), want fractions of the screen", s.x, s.y)
+		}
+	}
+	if len(perDepth) != 3 || perDepth[1] != 100 {
+		t.Errorf("depths = %v, want 100 stars in each of 3 layers", perDepth)
+	}
+	if !slices.Equal(stars, generateStars(300, 3)) {
+		t.Error("the same parameters should generate the same sky")
+	}
+}
+
+// TestStar_Position tests parallax panning and wrapping at the screen edges.
+func TestStar_Position(t *testing.T) {
+	s := star{x: 0.5, y: 0.5, depth: 0.5}
+	if x, y := s.position(0, 0, 800, 600); x != 400 || y != 300 {
+		t.Errorf("position = (%v, %v), want (400, 300)", x, y)
+	}
+	// Panning by 200 moves a half-depth star by 100
+	if x, _ := s.position(200, 0, 800, 600); x != 300 {
+		t.Errorf("x = %v, want 300", x)
+	}
+	// Stars leaving one edge come back at the other
+	if x, y := s.position(1000, -800, 800, 600); x != 700 || y != 100 {
+		t.Errorf("wrapped position = (%v, %v), want (700, 100)", x, y)
+	}
+	for i := 0; i < 100; i++ {
+		if b := s.twinkle(float32(i) / 10); b < 0.4 || b > 1 {
+			t.Fatalf("twinkle = %v, want 0.4 to 1", b)
+		}
+	}
+}
+
+// TestBackgroundRenderer_SetBackground tests the state derived from a
+// background.
+func TestBackgroundRenderer_SetBackground(t *testing.T) {
+	r := NewBackgroundRenderer(1280, 720)
+	if r.Persistent() || len(r.stars) != 0 {
+		t.Error("the default background should be plain")
+	}
+	if c := r.Color(); c != rl.NewColor(10, 10, 20, 255) {
+		t.Errorf("Color = %v, want the default (10, 10, 20)", c)
+	}
+
+	r.SetBackground(premium.StarfieldBackground(50, 2, 1, 2, 3, 4, 5, 6))
+	if len(r.stars) != 50 {
+		t.Errorf("stars = %d, want 50", len(r.stars))
+	}
+	if c := r.Color(); c != rl.NewColor(1, 2, 3, 255) {
+		t.Errorf("Color = %v, want the top color", c)
+	}
+
+	r.SetBackground(premium.GradientBackground(1, 2, 3, 4, 5, 6).WithPersistence(0.5))
+	if len(r.stars) != 0 {
+		t.Error("stars should be dropped with the starfield")
+	}
+	if !r.Persistent() || !r.accumFresh {
+		t.Error("a persistent background should start from a cleared accumulation")
+	}
+}
+
+// TestPostFXRenderer tests the effect stack and that post-processing
+// follows the quality level and stays inactive until loaded.
+func TestPostFXRenderer(t *testing.T) {
+	r := NewPostFXRenderer(1280, 720)
+	effects := []premium.PostEffect{
+		premium.Vignette(0.5, 0.4),
+		{Kind: premium.PostEffectKinds},
+		premium.FilmGrain(0.1),
+	}
+	r.SetEffects(effects)
+	got := r.Effects()
+	if len(got) != 2 || got[0].Kind != premium.EffectVignette || got[1].Kind != premium.EffectFilmGrain {
+		t.Fatalf("Effects() = %+v, want vignette and grain in order", got)
+	}
+	effects[0].Strength = 0
+	if r.Effects()[0].Strength != 0.5 {
+		t.Error("SetEffects should copy the stack")
+	}
+
+	r.ApplyQuality(premium.GetQualitySettings(premium.QualityLow))
+	if r.IsEnabled() {
+		t.Error("Low quality should disable post-processing")
+	}
+	r.ApplyQuality(premium.GetQualitySettings(premium.QualityHigh))
+	if !r.IsEnabled() {
+		t.Error("High quality should enable post-processing")
+	}
+	if r.Active() {
+		t.Error("post-processing should stay inactive until loaded")
+	}
+
+	sys := NewRenderSystem(1280, 720, "test")
+	sys.SetPostEffects(effects)
+	sys.resize(1920, 1080)
+	if sys.postfx.width != 1920 || sys.postfx.height != 1080 {
+		t.Errorf("postfx size = %dx%d, want 1920x1080", sys.postfx.width, sys.postfx.height)
+	}
+	sys.SetPostEffects(nil)
+	if len(sys.PostEffects()) != 0 {
+		t.Error("SetPostEffects(nil) should clear the stack")
+	}
+}
+
+// TestMetaballFalloff tests the density falloff and the texture drawn
+// with it.
+func TestMetaballFalloff(t *testing.T) {
+	if f := metaballFalloff(0); f != 1 {
+		t.Errorf("falloff(0) = %v, want 1", f)
+	}
+	if f := metaballFalloff(1); f != 0 {
+		t.Errorf("falloff(1) = %v, want 0", f)
+	}
+	for d := 0.1; d < 1; d += 0.1 {
+		if metaballFalloff(d) >= metaballFalloff(d-0.1) {
+			t.Fatalf("falloff should decrease, falloff(%v) >= falloff(%v)", d, d-0.1)
+		}
+	}
+
+	sheet := newFalloffSheet()
+	center := sheet.image.NRGBAAt(falloffSize/2, falloffSize/2)
+	if center.A < 250 || center.R != center.A {
+		t.Errorf("center = %v, want opaque with color equal to alpha", center)
+	}
+	if corner := sheet.image.NRGBAAt(0, 0); corner.A != 0 {
+		t.Errorf("corner = %v, want transparent", corner)
+	}
+
+	// Colors are weighted by alpha and everything is scaled to the
+	// density range
+	if r, g, b, a := metaballTint(255, 100, 0, 255); r != 128 || g != 50 || b != 0 || a != 128 {
+		t.Errorf("tint = (%d, %d, %d, %d), want (128, 50, 0, 128)", r, g, b, a)
+	}
+	if r, _, _, a := metaballTint(255, 0, 0, 0); r != 0 || a != 0 {
+		t.Errorf("invisible particle tint = (%d, %d), want no density", r, a)
+	}
+}
+
+// TestRenderSystem_Metaballs tests which particles become metaballs and
+// that metaballs stay inactive until loaded.
+func TestRenderSystem_Metaballs(t *testing.T) {
+	sys := NewRenderSystem(1280, 720, "test")
+	if sys.Metaballs() != nil {
+		t.Error("particles should be circles by default")
+	}
+	m := premium.NewMetaballs()
+	sys.SetMetaballs(m)
+	if sys.Metaballs() != m {
+		t.Error("SetMetaballs did not set the configuration")
+	}
+	if sys.metaballs.Active() {
+		t.Error("metaballs should stay inactive until loaded")
+	}
+
+	sys.AddSpriteSheet("spark", NewSpriteSheet(image.NewNRGBA(image.Rect(0, 0, 8, 8)), 8, 8))
+	circle := ecs.NewEntity("circle", nil)
+	sprite := ecs.NewEntity("sprite", []ecs.Component{components.NewSprite("spark")})
+	unknown := ecs.NewEntity("unknown", []ecs.Component{components.NewSprite("missing")})
+	if !sys.isMetaball(circle) || sys.isMetaball(sprite) || !sys.isMetaball(unknown) {
+		t.Error("particles drawn as circles should be metaballs, sprites not")
+	}
+
+	sys.resize(1920, 1080)
+	if w, h := sys.metaballs.densitySize(); w != 960 || h != 540 {
+		t.Errorf("density size = %dx%d, want 960x540", w, h)
+	}
+}
+
+// TestLightTint tests that lights take their color from the particle,
+// weighted by its alpha and the intensity.
+func TestLightTint(t *testing.T) {
+	if r, g, b, a := lightTint(200, 100, 0, 255, 0.5); r != 100 || g != 50 || b != 0 || a != 255 {
+		t.Errorf("tint = (%d, %d, %d, %d), want (100, 50, 0, 255)", r, g, b, a)
+	}
+	if r, _, _, _ := lightTint(200, 100, 0, 0, 1); r != 0 {
+		t.Errorf("invisible particle light = %d, want 0", r)
+	}
+	if r, _, _, _ := lightTint(200, 100, 0, 255, 4); r != 255 {
+		t.Errorf("bright light = %d, want clamped to 255", r)
+	}
+}
+
+// TestRenderSystem_Lighting tests the lighting settings and that lighting
+// stays inactive until loaded.
+func TestRenderSystem_Lighting(t *testing.T) {
+	sys := NewRenderSystem(1280, 720, "test")
+	if sys.Lighting() != nil {
+		t.Error("lighting should be off by default")
+	}
+	l := premium.NewLighting()
+	sys.SetLighting(l)
+	if sys.Lighting() != l {
+		t.Error("SetLighting did not set the configuration")
+	}
+	if sys.lights.Active() {
+		t.Error("lighting should stay inactive until loaded")
+	}
+	if sys.Passes()&PassLighting == 0 {
+		t.Error("the lighting pass should be on by default")
+	}
+
+	sys.resize(1920, 1080)
+	if w, h := sys.lights.bufferSize(); w != 480 || h != 270 {
+		t.Errorf("light buffer = %dx%d, want 480x270", w, h)
+	}
+}
+
+// juiceRecorder records the screen effects of a juice system.
+type juiceRecorder struct {
+	shake, pulse, flash float32
+}
+
+func (r *juiceRecorder) ApplyShake(intensity, duration float32) { r.shake = intensity }
+func (r *juiceRecorder) ApplyPulse(scale, duration float32)     { r.pulse = scale }
+func (r *juiceRecorder) ApplyFlash(alpha, duration float32)     { r.flash = alpha }
+
+// TestJuiceSystem_Levels tests that reactions scale with the juice level
+// and that JuiceOff does nothing.
+func TestJuiceSystem_Levels(t *testing.T) {
+	react := func(level premium.JuiceLevel) (*juiceRecorder, int) {
+		em := ecs.NewEntityManager()
+		screen := &juiceRecorder{}
+		sys := NewJuiceSystem(screen, level)
+		sys.Trigger(JuicePresetSwitch, 0, 0)
+		sys.Trigger(JuiceRepelRelease, 100, 100)
+		sys.update(em, 0.016)
+		return screen, countParticles(em)
+	}
+
+	off, n := react(premium.JuiceOff)
+	if *off != (juiceRecorder{}) || n != 0 {
+		t.Errorf("JuiceOff reacted: %+v, %d particles", *off, n)
+	}
+	subtle, _ := react(premium.JuiceSubtle)
+	intense, n := react(premium.JuiceIntense)
+	if subtle.shake <= 0 || intense.shake <= subtle.shake {
+		t.Errorf("shake subtle = %f, intense = %f, want increasing", subtle.shake, intense.shake)
+	}
+	if subtle.flash != 0 || intense.flash == 0 {
+		t.Errorf("flash subtle = %f, intense = %f, want only intense", subtle.flash, intense.flash)
+	}
+	if n != premium.GetJuiceConfig(premium.JuiceIntense).ParticleBurst {
+		t.Errorf("burst = %d particles, want %d", n, premium.GetJuiceConfig(premium.JuiceIntense).ParticleBurst)
+	}
+
+	sys := NewJuiceSystem(&juiceRecorder{}, premium.JuiceOff)
+	sys.SetLevel(premium.JuiceIntense)
+	if sys.Level() != premium.JuiceIntense {
+		t.Errorf("Level() = %v, want Intense", sys.Level())
+	}
+}
+
+// TestJuiceSystem_Bursts tests that releases burst outwards from the
+// cursor and locks inwards from a ring around it.
+func TestJuiceSystem_Bursts(t *testing.T) {
+	for _, tt := range []struct {
+		event   JuiceEvent
+		outward bool
+	}{
+		{JuiceRepelRelease, true},
+		{JuiceAttractLock, false},
+	} {
+		em := ecs.NewEntityManager()
+		sys := NewJuiceSystem(&juiceRecorder{}, premium.JuiceNormal)
+		sys.Trigger(tt.event, 200, 300)
+		sys.update(em, 0.016)
+
+		particles := em.FilterByMask(components.MaskParticle | components.MaskPosition | components.MaskVelocity)
+		if len(particles) == 0 {
+			t.Fatalf("event %d spawned no burst", tt.event)
+		}
+		for _, e := range particles {
+			pos := e.Get(components.MaskPosition).(*components.Position)
+			vel := e.Get(components.MaskVelocity).(*components.Velocity)
+			dx, dy := pos.X-200, pos.Y-300
+			if tt.outward && (dx != 0 || dy != 0) {
+				t.Fatalf("outward burst starts at (%f, %f), want the cursor", pos.X, pos.Y)
+			}
+			if !tt.outward {
+				if d := math.Hypot(float64(dx), float64(dy)); math.Abs(d-burstRing) > 0.01 {
+					t.Fatalf("inward burst starts %f from the cursor, want %d", d, burstRing)
+				}
+				if dx*vel.X+dy*vel.Y >= 0 {
+					t.Fatal("inward burst particle flies outwards")
+				}
+			}
+		}
+	}
+}
+
+// TestJuiceSystem_MassDeath tests the mass death thresholds and cooldown.
+func TestJuiceSystem_MassDeath(t *testing.T) {
+	em := ecs.NewEntityManager()
+	screen := &juiceRecorder{}
+	sys := NewJuiceSystem(screen, premium.JuiceNormal)
+
+	sys.MassDeath(massDeathMin-1, massDeathMin)
+	sys.MassDeath(massDeathMin, massDeathMin*10)
+	sys.update(em, 0.016)
+	if screen.shake != 0 {
+		t.Fatal("too few deaths should not react")
+	}
+
+	sys.MassDeath(massDeathMin, massDeathMin*2)
+	sys.update(em, 0.016)
+	if screen.shake == 0 {
+		t.Fatal("a mass death should shake the screen")
+	}
+
+	*screen = juiceRecorder{}
+	sys.MassDeath(massDeathMin, massDeathMin*2)
+	sys.update(em, 0.016)
+	if screen.shake != 0 {
+		t.Error("mass deaths during the cooldown should not react")
+	}
+
+	sys.update(em, massDeathCooldown)
+	sys.MassDeath(massDeathMin, massDeathMin*2)
+	sys.update(em, 0.016)
+	if screen.shake == 0 {
+		t.Error("mass deaths after the cooldown should react")
+	}
+}
+
+// TestLifetimeSystem_OnExpire tests that the expire callback counts the
+// expired and aged particles.
+func TestLifetimeSystem_OnExpire(t *testing.T) {
+	em := ecs.NewEntityManager()
+	for i, ttl := range []float32{1, 1, 5} {
+		em.Add(ecs.NewEntity(fmt.Sprintf("p%d", i), []ecs.Component{
+			components.NewLifetime().WithTTL(ttl),
+			components.NewParticle(),
+		}))
+	}
+	lifetime := NewLifetimeSystem()
+	var expired, total int
+	calls := 0
+	lifetime.SetOnExpire(func(e, n int) {
+		expired, total = e, n
+		calls++
+	})
+
+	lifetime.update(em, 0.5)
+	if calls != 0 {
+		t.Errorf("callback called %d times without expired particles", calls)
+	}
+	lifetime.update(em, 1)
+	if calls != 1 || expired != 2 || total != 3 {
+		t.Errorf("onExpire(%d, %d) called %d times, want (2, 3) once", expired, total, calls)
+	}
+}
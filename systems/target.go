@@ -0,0 +1,51 @@
+package systems
+
+import (
+	"github.com/andygeiss/ecs"
+	"github.com/deltatree/showcase/components"
+)
+
+// TargetSystem steers entities towards their Target position.
+// Each active target acts as a critically damped spring: the acceleration
+// grows with the distance to the goal and is damped by the current
+// velocity, so particles fly in quickly and settle without oscillating.
+//
+// The spring is added on top of the acceleration computed by
+// GravitySystem, so the system must run after GravitySystem and before
+// PhysicsSystem. Attractors keep working while a figure is formed,
+// letting the mouse push particles out of shape.
+type targetSystem struct{}
+
+// NewTargetSystem creates a new target steering system.
+func NewTargetSystem() ecs.System {
+	return &targetSystem{}
+}
+
+func (s *targetSystem) Setup() {}
+
+func (s *targetSystem) Process(em ecs.EntityManager) (state int) {
+	entities := query(em, components.MaskPosition|components.MaskVelocity|
+		components.MaskAcceleration|components.MaskTarget)
+
+	for _, e := range entities {
+		target := e.Get(components.MaskTarget).(*components.Target)
+		if !target.Active {
+			continue
+		}
+		pos := e.Get(components.MaskPosition).(*components.Position)
+		vel := e.Get(components.MaskVelocity).(*components.Velocity)
+		acc := e.Get(components.MaskAcceleration).(*components.Acceleration)
+
+		k := target.Strength * target.Strength
+		c := 2 * target.Strength
+
+		acc.Add(
+			(target.X-pos.X)*k-vel.X*c,
+			(target.Y-pos.Y)*k-vel.Y*c,
+		)
+	}
+
+	return ecs.StateEngineContinue
+}
+
+func (s *targetSystem) Teardown() {}
@@ -0,0 +1,108 @@
+package systems
+
+import (
+	"github.com/deltatree/showcase/components"
+	"github.com/deltatree/showcase/premium"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// TrailStyle selects how TrailRenderer draws a trail.
+type TrailStyle int
+
+const (
+	// TrailLines draws fading line segments that thin out towards the end.
+	TrailLines TrailStyle = iota
+	// TrailCircles draws fading circles that shrink towards the end.
+	TrailCircles
+)
+
+// TrailRenderer draws the recorded positions of a Trail behind a particle.
+// Older positions fade out and get thinner; at most length positions are
+// drawn, so the quality level can shorten trails without touching the
+// particles.
+type TrailRenderer struct {
+	enabled bool
+	length  int
+	style   TrailStyle
+}
+
+// NewTrailRenderer creates a new trail renderer.
+func NewTrailRenderer(length int, style TrailStyle) *TrailRenderer {
+	return &TrailRenderer{
+		enabled: length > 0,
+		length:  length,
+		style:   style,
+	}
+}
+
+// RenderTrail draws the trail of a particle, oldest segment first.
+func (r *TrailRenderer) RenderTrail(
+	pos *components.Position,
+	trail *components.Trail,
+	col *components.Color,
+	size *components.Size,
+	shakeX, shakeY float32,
+) {
+	if !r.enabled {
+		return
+	}
+	n := min(trail.Len(), r.length)
+	if n == 0 {
+		return
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		// Fade from the particle towards the oldest position
+		fade := 1 - float32(i+1)/float32(n+1)
+		color := rl.NewColor(col.R, col.G, col.B, uint8(float32(col.A)*fade*0.6))
+		radius := size.Radius * fade
+		x, y := trail.At(i)
+
+		switch r.style {
+		case TrailCircles:
+			rl.DrawCircle(int32(x+shakeX), int32(y+shakeY), radius, color)
+		default:
+			// Segment towards the next newer position (or the particle)
+			nx, ny := pos.X, pos.Y
+			if i > 0 {
+				nx, ny = trail.At(i - 1)
+			}
+			rl.DrawLineEx(
+				rl.NewVector2(x+shakeX, y+shakeY),
+				rl.NewVector2(nx+shakeX, ny+shakeY),
+				max(radius, 1), color,
+			)
+		}
+	}
+}
+
+// SetEnabled enables or disables trails.
+func (r *TrailRenderer) SetEnabled(enabled bool) {
+	r.enabled = enabled
+}
+
+// SetLength sets the maximum number of positions drawn.
+func (r *TrailRenderer) SetLength(length int) {
+	r.length = length
+}
+
+// SetStyle sets how trails are drawn.
+func (r *TrailRenderer) SetStyle(style TrailStyle) {
+	r.style = style
+}
+
+// ApplyQuality applies quality settings to trail rendering.
+func (r *TrailRenderer) ApplyQuality(q premium.QualitySettings) {
+	r.enabled = q.TrailLength > 0
+	r.length = q.TrailLength
+}
+
+// IsEnabled returns whether trails are drawn.
+func (r *TrailRenderer) IsEnabled() bool {
+	return r.enabled
+}
+
+// Length returns the maximum number of positions drawn.
+func (r *TrailRenderer) Length() int {
+	return r.length
+}